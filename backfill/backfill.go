@@ -0,0 +1,40 @@
+package main
+
+import (
+	"api/server/db"
+	"api/server/graph"
+	"context"
+	"log"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// backfill enrolls the students who have results in a course recorded before
+// enrollments existed. It only needs to run once after upgrading, and is safe
+// to run again.
+
+func init() {
+	viper.SetConfigFile(".env")
+	if err := viper.ReadInConfig(); err != nil {
+		panic(err)
+	}
+	os.Setenv("DATABASE_URL", viper.GetString("DATABASE_URL"))
+}
+
+func main() {
+	client := db.NewClient()
+	if err := client.Prisma.Connect(); err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := client.Prisma.Disconnect(); err != nil {
+			panic(err)
+		}
+	}()
+
+	resolver := &graph.Resolver{Client: client}
+	if err := resolver.BackfillEnrollments(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
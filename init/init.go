@@ -65,7 +65,7 @@ func main() {
 
 	ctx := context.Background()
 	for _, teacher := range teachers {
-		if teacher.RoleLevel < 1 && teacher.RoleLevel > 3 {
+		if teacher.RoleLevel < 1 || teacher.RoleLevel > 3 {
 			continue
 		}
 		_, _ = client.User.UpsertOne(
@@ -5,7 +5,7 @@ import { ApolloClient, ApolloError, gql, NormalizedCacheObject, useQuery } from
 const GET_STUDENTS_IN_COURSE = gql`
   query StudentsInCourse($courseID: ID!) {
     studentsInCourse(courseID: $courseID) {
-      id
+      id: databaseID
       email
       name
       surname
@@ -65,17 +65,17 @@ const GET_DASHBOARD_FLAT = gql`
   query FlatSummary($courseID: ID!) {
     flatSummary(courseID: $courseID) {
       students {
-        id
+        id: databaseID
         name
         surname
       }
       plos {
-        id
+        id: databaseID
         title
         description
       }
       los {
-        id
+        id: databaseID
         title
         levels {
           level
@@ -17,7 +17,7 @@ export const CourseStaticPaths: GetStaticPaths = async (context) => {
     const GET_COURSES = gql`
     query Courses($programID: ID!) {
       courses(programID: $programID) {
-        id
+        id: databaseID
         name
         description
         semester
@@ -55,7 +55,7 @@ export const ProgramStaticPaths: GetStaticPaths = async (context) => {
     const GET_PROGRAMS = gql`
     query Programs {
       programs {
-        id
+        id: databaseID
         name
         description
   }}`
@@ -100,16 +100,42 @@ func main() {
 		return true
 	}
 	config.AllowCredentials = true
-	config.AllowHeaders = append(config.AllowHeaders, "Authorization")
+	config.AllowHeaders = append(config.AllowHeaders, "Authorization", "Idempotency-Key")
 	r.Use(cors.New(config))
 
+	resolver := &graph.Resolver{
+		Client:            client,
+		IdempotencyWindow: viper.GetDuration("IDEMPOTENCY_WINDOW"),
+		TrashRetention:    viper.GetDuration("TRASH_RETENTION"),
+		AuditRetention:    viper.GetDuration("AUDIT_RETENTION"),
+	}
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := resolver.PurgeTrash(ctx); err != nil {
+				log.Println(err)
+			}
+			if err := resolver.PurgeAuditLog(ctx); err != nil {
+				log.Println(err)
+			}
+			<-ticker.C
+		}
+	}()
+
 	auth.SetAuthRouter(r.Group("/auth"), client, rdb, ctx)
 	r.POST("/query", auth.GetMiddleware(rdb, ctx), func(c *gin.Context) {
-		handler.NewDefaultServer(
+		if key := c.GetHeader("Idempotency-Key"); key != "" {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "idempotency_key", key))
+		}
+		srv := handler.NewDefaultServer(
 			generated.NewExecutableSchema(
-				generated.Config{Resolvers: &graph.Resolver{Client: client}},
+				generated.Config{Resolvers: resolver},
 			),
-		).ServeHTTP(c.Writer, c.Request)
+		)
+		srv.AroundFields(resolver.AuditMutations)
+		srv.AroundFields(resolver.RequireActiveAccount)
+		srv.ServeHTTP(c.Writer, c.Request)
 	})
 	r.Run(":" + viper.GetString("API_PORT"))
 }
@@ -56,7 +56,7 @@ function NavHistory({ courseID }: { courseID: string }) {
   const { data, loading } = useQuery<{ course: CourseModel }, { courseID: string }>(gql`
     query Course($courseID: ID!) {
       course(courseID: $courseID) {
-        id
+        id: databaseID
         name
         programID
     }}
@@ -97,7 +97,7 @@ export const getStaticProps: GetStaticProps<PageProps> = async (context) => {
     const GET_COURSE = gql`
     query CourseDescription($courseID: ID!) {
       course(courseID: $courseID) {
-        id
+        id: databaseID
         name
         programID
   }}`
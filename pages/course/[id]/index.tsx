@@ -41,7 +41,7 @@ export const getStaticProps: GetStaticProps<{ course: CourseModel }> = async (co
     const GET_COURSE = gql`
     query CourseDescription($courseID: ID!) {
       course(courseID: $courseID) {
-        id
+        id: databaseID
         name
         description
         programID
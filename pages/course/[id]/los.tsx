@@ -486,7 +486,7 @@ export const getServerSideProps: GetServerSideProps<{course: CourseModel, los: L
 const GET_COURSE = gql`
   query CourseDescription($courseID: ID!) {
     course(courseID: $courseID) {
-      id
+      id: databaseID
       name
       programID
       ploGroupID
@@ -495,7 +495,7 @@ const GET_COURSE = gql`
 const GET_LOS = gql`
   query LOs($courseID: ID!) {
     los(courseID: $courseID) {
-      id
+      id: databaseID
       title
       levels {
         level
@@ -536,7 +536,7 @@ const DELETE_LOLEVEL = gql`
 const GET_PLOS = gql`
   query PLOs($ploGroupID: ID!) {
     plos(ploGroupID: $ploGroupID) {
-      id
+      id: databaseID
       title
       description
       ploGroupID
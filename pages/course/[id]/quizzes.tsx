@@ -446,7 +446,7 @@ export const getServerSideProps: GetServerSideProps<{course: CourseModel, quizze
 const GET_COURSE = gql`
   query CourseDescription($courseID: ID!) {
     course(courseID: $courseID) {
-      id
+      id: databaseID
       name
       programID
       teacherID
@@ -454,7 +454,7 @@ const GET_COURSE = gql`
 const GET_LOS = gql`
 query LOs($courseID: ID!) {
   los(courseID: $courseID) {
-    id
+    id: databaseID
     title
     levels {
       level
@@ -470,11 +470,11 @@ query LOs($courseID: ID!) {
 const GET_QUIZZES = gql`
   query Quizzes($courseID: ID!) {
     quizzes(courseID: $courseID) {
-      id
+      id: databaseID
       name
       createdAt
       questions {
-        id
+        id: databaseID
         title
         maxScore
         loLinks {
@@ -156,7 +156,7 @@ export const getServerSideProps: GetServerSideProps<{course: CourseModel, ploGro
 const GET_COURSE = gql`
   query CourseDetail($courseID: ID!) {
     course(courseID: $courseID) {
-      id
+      id: databaseID
       name
       description
       semester
@@ -168,13 +168,13 @@ const GET_COURSE = gql`
 const GET_PLOGROUPS = gql`
   query PLOGroups($programID: ID!) {
     ploGroups(programID: $programID) {
-      id
+      id: databaseID
       name
 }}`
 const EDIT_COURSE = gql`
   mutation EditCourse($id: ID!, $input: CreateCourseInput!) {
     editCourse(id: $id, input: $input) {
-      id
+      id: databaseID
 }}`
 const DELETE_COURSE = gql`
   mutation DeleteCourse($id: ID!) {
@@ -70,7 +70,7 @@ export const getStaticProps: GetStaticProps<{course: CourseModel, students: Stud
   const GET_COURSE = gql`
     query CourseDescription($courseID: ID!) {
       course(courseID: $courseID) {
-        id
+        id: databaseID
         name
         programID
   }}`
@@ -84,7 +84,7 @@ export const getStaticProps: GetStaticProps<{course: CourseModel, students: Stud
   const GET_STUDENTS_IN_COURSE = gql`
     query StudentsInCourse($courseID: ID!) {
       studentsInCourse(courseID: $courseID) {
-        id
+        id: databaseID
         email
         name
         surname
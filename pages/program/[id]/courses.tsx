@@ -137,7 +137,7 @@ export const getStaticPaths: GetStaticPaths = ProgramStaticPaths
 const GET_COURSES = gql`
   query Courses($programID: ID!) {
     courses(programID: $programID) {
-      id
+      id: databaseID
       name
       description
       semester
@@ -36,7 +36,7 @@ interface CreateCourseResponse {
 const CREATE_COURSE = gql`
   mutation CreateCourse($programID: ID!, $input: CreateCourseInput!) {
     createCourse(programID: $programID, input: $input) {
-      id
+      id: databaseID
       name
       description
       semester
@@ -133,7 +133,7 @@ export const getServerSideProps: GetServerSideProps<{ programID: string, ploGrou
     const GET_PLOGROUPS = gql`
     query PLOGroups($programID: ID!) {
       ploGroups(programID: $programID) {
-        id
+        id: databaseID
         name
   }}`
     const { id: programID } = context.params as Params
@@ -87,7 +87,7 @@ export const getStaticPaths: GetStaticPaths = ProgramStaticPaths
 const GET_STUDENTS_IN_PROGRAM = gql`
   query StudentsInProgram($programID: ID!) {
     studentsInProgram(programID: $programID) {
-      id
+      id: databaseID
       email
       name
       surname
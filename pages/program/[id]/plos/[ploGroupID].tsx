@@ -114,7 +114,7 @@ export const getServerSideProps: GetServerSideProps<{programID: string, ploGroup
             }
           }
           students {
-            id
+            id: databaseID
             email
             name
             surname
@@ -363,13 +363,13 @@ export const getServerSideProps: GetServerSideProps<{programID: string, ploGroup
 const GET_PLOGROUPS = gql`
   query PLOGroups($programID: ID!) {
     ploGroups(programID: $programID) {
-      id
+      id: databaseID
       name
 }}`
 const GET_PLOS = gql`
   query PLOs($ploGroupID: ID!) {
     plos(ploGroupID: $ploGroupID) {
-      id
+      id: databaseID
       title
       description
       ploGroupID
@@ -377,7 +377,7 @@ const GET_PLOS = gql`
 const CREATE_PLOGROUP = gql`
   mutation CreatePLOGroup($programID: ID!, $name: String!, $input: [CreatePLOsInput!]!) {
     createPLOGroup(programID: $programID, name: $name, input: $input) {
-      id
+      id: databaseID
       name
 }}`
 const ADD_PLOS = gql`
@@ -388,7 +388,7 @@ const ADD_PLOS = gql`
 const CREATE_PLO = gql`
   mutation CreatePLO($ploGroupID: ID!, $input: CreatePLOInput!) {
     createPLO(ploGroupID: $ploGroupID, input: $input) {
-      id
+      id: databaseID
       title
       description
       ploGroupID
@@ -406,10 +406,10 @@ mutation DeletePLO($id: ID!) {
 const EDIT_PLOGROUP = gql`
   mutation EditPLOGroup($id: ID!, $name: String!) {
     editPLOGroup(id: $id, name: $name) {
-      id  
+      id: databaseID  
 }}`
 const EDIT_PLO = gql`
   mutation EditPLO($id: ID!, $title: String!, $description: String!) {
     editPLO(id: $id, title: $title, description: $description) {
-      id
+      id: databaseID
 }}`
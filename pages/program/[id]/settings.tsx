@@ -103,7 +103,7 @@ export const getServerSideProps: GetServerSideProps<{program: ProgramModel}> = a
 const GET_PROGRAM = gql`
   query Program($programID: ID!) {
     program(programID: $programID) {
-      id
+      id: databaseID
       name
       description
       teacherID
@@ -111,5 +111,5 @@ const GET_PROGRAM = gql`
 const EDIT_PROGRAM = gql`
   mutation EditProgram($id: ID!, $input: CreateProgramInput!) {
     editProgram(id: $id, input: $input) {
-      id
+      id: databaseID
 }}`
@@ -92,7 +92,7 @@ function CreateProgramModal({ show, setShow }: { show: boolean, setShow: Dispatc
   const CREATE_PROGRAM = gql`
     mutation CreateProgram($input: CreateProgramInput!) {
       createProgram(input: $input) {
-        id
+        id: databaseID
         name
         description
   }}`
@@ -175,7 +175,7 @@ export const getStaticProps: GetStaticProps<{ programs: ProgramModel[] }> = asyn
     const GET_PROGRAMS = gql`
     query Programs {
       programs {
-        id
+        id: databaseID
         name
         description
   }}`
@@ -272,7 +272,7 @@ export const getStaticPaths: GetStaticPaths = async (context) => {
     const GET_STUDENTS = gql`
     query Students {
       students {
-        id
+        id: databaseID
   }}`
     const client = initializeApollo(process.env.SSG_SECRET)
     const { data } = await client.query<{ students: StudentModel[] }>({
@@ -303,7 +303,7 @@ interface StudentModel {
 const GET_STUDENT = gql`
   query Student($studentID: ID!) {
     student(studentID: $studentID) {
-      id
+      id: databaseID
       email
       name
       surname
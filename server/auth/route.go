@@ -69,6 +69,9 @@ func SetAuthRouter(r *gin.RouterGroup, client *db.PrismaClient, rdb *redis.Clien
 		if teacher == nil && student == nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 			return
+		} else if teacher != nil && !teacher.Active {
+			c.JSON(http.StatusForbidden, gin.H{"error": "account is deactivated"})
+			return
 		} else if teacher != nil {
 			isTeacher = true
 			level = teacher.Role
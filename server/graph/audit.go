@@ -0,0 +1,318 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// DefaultAuditRetention is how long audit log entries are kept when
+// Resolver.AuditRetention isn't set.
+const DefaultAuditRetention = 365 * 24 * time.Hour
+
+// auditTarget names the entity a mutation changes and how to find its keys
+// in the arguments. When the keys are known before the mutation runs, the
+// entity is loaded through Node before and after it; otherwise the result of
+// the mutation is recorded as the after value.
+type auditTarget struct {
+	entity string
+	keys   func(args map[string]interface{}) []string
+}
+
+func auditArgs(names ...string) func(args map[string]interface{}) []string {
+	return func(args map[string]interface{}) []string {
+		keys := []string{}
+		for _, name := range names {
+			switch value := args[name].(type) {
+			case string:
+				keys = append(keys, value)
+			case int:
+				keys = append(keys, strconv.Itoa(value))
+			default:
+				return nil
+			}
+		}
+		return keys
+	}
+}
+
+var trashEntities = map[model.TrashType]string{
+	model.TrashTypeProgram:  "Program",
+	model.TrashTypePloGroup: "PLOGroup",
+	model.TrashTypeCourse:   "Course",
+	model.TrashTypeQuiz:     "Quiz",
+}
+
+var auditTargets = map[string]auditTarget{
+	"createProgram":        {entity: "Program"},
+	"editProgram":          {"Program", auditArgs("id")},
+	"createPLOGroup":       {entity: "PLOGroup"},
+	"addPLOs":              {"PLOGroup", auditArgs("ploGroupID")},
+	"editPLOGroup":         {"PLOGroup", auditArgs("id")},
+	"revisePLOGroup":       {"PLOGroup", auditArgs("id")},
+	"setPLOMapping":        {"PLO", auditArgs("oldPLOID")},
+	"deletePLOGroup":       {"PLOGroup", auditArgs("id")},
+	"createPLO":            {entity: "PLO"},
+	"editPLO":              {"PLO", auditArgs("id")},
+	"deletePLO":            {"PLO", auditArgs("id")},
+	"createCourse":         {entity: "Course"},
+	"editCourse":           {"Course", auditArgs("id")},
+	"deleteCourse":         {"Course", auditArgs("id")},
+	"cloneCourse":          {entity: "Course"},
+	"remapLOLinks":         {"Course", auditArgs("courseID")},
+	"createLOs":            {"Course", auditArgs("courseID")},
+	"addCourseStaff":       {"Course", auditArgs("courseID")},
+	"setCourseStaffRole":   {"Course", auditArgs("courseID")},
+	"removeCourseStaff":    {"Course", auditArgs("courseID")},
+	"createLO":             {entity: "LO"},
+	"editLO":               {"LO", auditArgs("id")},
+	"deleteLO":             {"LO", auditArgs("id")},
+	"createLOLevel":        {"LO", auditArgs("loID")},
+	"editLOLevel":          {"LOLevel", auditArgs("id", "level")},
+	"deleteLOLevel":        {"LOLevel", auditArgs("id", "level")},
+	"createLOLink":         {"LO", auditArgs("loID")},
+	"deleteLOLink":         {"LO", auditArgs("loID")},
+	"createQuiz":           {entity: "Quiz"},
+	"editQuiz":             {"Quiz", auditArgs("id")},
+	"setCategoryWeights":   {"Course", auditArgs("courseID")},
+	"createGradeScheme":    {entity: "GradeScheme"},
+	"editGradeScheme":      {"GradeScheme", auditArgs("id")},
+	"setCourseGradeScheme": {"Course", auditArgs("courseID")},
+	"deleteQuiz":           {"Quiz", auditArgs("id")},
+	"addQuestion":          {"Quiz", auditArgs("quizID")},
+	"editQuestion":         {"Question", auditArgs("id")},
+	"deleteQuestion":       {"Question", auditArgs("id")},
+	"createQuestionLink": {"Question", func(args map[string]interface{}) []string {
+		if input, ok := args["input"].(*model.CreateQuestionLinkInput); ok && input != nil {
+			return []string{input.QuestionID}
+		}
+		return nil
+	}},
+	"deleteQuestionLink": {"Question", func(args map[string]interface{}) []string {
+		if input, ok := args["input"].(model.DeleteQuestionLinkInput); ok {
+			return []string{input.QuestionID}
+		}
+		return nil
+	}},
+	"upsertQuestionResults": {"Quiz", auditArgs("quizID")},
+	"createStudents":        {entity: "User"},
+	"enroll":                {"Course", auditArgs("courseID")},
+	"enrollStudents":        {"Course", auditArgs("courseID")},
+	"unenroll":              {"Course", auditArgs("courseID")},
+	"createSection":         {entity: "Section"},
+	"editSection":           {"Section", auditArgs("id")},
+	"deleteSection":         {"Section", auditArgs("id")},
+	"setStudentSection":     {"Course", auditArgs("courseID")},
+	"addSectionStaff":       {"Section", auditArgs("sectionID")},
+	"removeSectionStaff":    {"Section", auditArgs("sectionID")},
+	"finalizeCourse":        {"Course", auditArgs("id")},
+	"unlockCourse":          {"Course", auditArgs("id")},
+	"createCatalogCourse":   {entity: "CatalogCourse"},
+	"editCatalogCourse":     {"CatalogCourse", auditArgs("id")},
+	"setCourseCatalog":      {"Course", auditArgs("courseID")},
+	"createTerm":            {entity: "Term"},
+	"editTerm":              {"Term", auditArgs("id")},
+	"setTermStatus":         {"Term", auditArgs("id")},
+	"createTeacher":         {entity: "Teacher"},
+	"editTeacher":           {"Teacher", auditArgs("id")},
+	"setTeacherRole":        {"Teacher", auditArgs("id")},
+	"deactivateTeacher":     {"Teacher", auditArgs("id")},
+	"reactivateTeacher":     {"Teacher", auditArgs("id")},
+	"trash":                 {keys: auditArgs("id")},
+	"restore":               {keys: auditArgs("id")},
+}
+
+// auditSnapshots load what a mutation changes when it isn't part of the
+// target's Node, such as the LOs, links, weights, staff and enrollments of a
+// course. They replace the Node snapshot of those mutations.
+var auditSnapshots = map[string]func(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error){
+	"createLOs": func(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error) {
+		courseID, _ := args["courseID"].(string)
+		return r.Client.LO.FindMany(
+			db.LO.CourseID.Equals(courseID),
+		).With(
+			db.LO.Levels.Fetch(),
+		).Exec(ctx)
+	},
+	"createLOLink": loLinksSnapshot,
+	"deleteLOLink": loLinksSnapshot,
+	"setCategoryWeights": func(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error) {
+		courseID, _ := args["courseID"].(string)
+		return r.Client.CategoryWeight.FindMany(
+			db.CategoryWeight.CourseID.Equals(courseID),
+		).Exec(ctx)
+	},
+	"addCourseStaff":     courseStaffSnapshot,
+	"setCourseStaffRole": courseStaffSnapshot,
+	"removeCourseStaff":  courseStaffSnapshot,
+	"enroll":             enrollmentsSnapshot,
+	"enrollStudents":     enrollmentsSnapshot,
+	"unenroll":           enrollmentsSnapshot,
+	"setStudentSection":  enrollmentsSnapshot,
+}
+
+func loLinksSnapshot(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error) {
+	loID, _ := args["loID"].(string)
+	return r.Client.LOlink.FindMany(
+		db.LOlink.LoID.Equals(loID),
+	).Exec(ctx)
+}
+
+func courseStaffSnapshot(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error) {
+	courseID, _ := args["courseID"].(string)
+	teacherID, _ := args["teacherID"].(string)
+	return r.Client.CourseStaff.FindMany(
+		db.CourseStaff.CourseID.Equals(courseID),
+		db.CourseStaff.TeacherID.Equals(teacherID),
+	).Exec(ctx)
+}
+
+// enrollmentsSnapshot loads the enrollments of the students in studentID or
+// studentIDs.
+func enrollmentsSnapshot(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error) {
+	courseID, _ := args["courseID"].(string)
+	studentIDs, _ := args["studentIDs"].([]string)
+	if studentID, ok := args["studentID"].(string); ok {
+		studentIDs = append(studentIDs, studentID)
+	}
+	return r.Client.Enrollment.FindMany(
+		db.Enrollment.CourseID.Equals(courseID),
+		db.Enrollment.StudentID.In(studentIDs),
+	).Exec(ctx)
+}
+
+// AuditMutations is a field middleware that writes an audit log entry for
+// every mutation, whether it succeeds or not, including those rejected
+// because the viewer isn't signed in. A failure to write the entry is logged
+// and doesn't fail the mutation.
+//
+// The before and after snapshots are read outside the mutation's
+// transaction, so a concurrent change can show up in them. They are
+// best-effort; the arguments and the error are exactly what the mutation got.
+func (r *Resolver) AuditMutations(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" {
+		return next(ctx)
+	}
+	operation := fc.Field.Name
+	target := auditTargets[operation]
+	if trashType, ok := fc.Args["type"].(model.TrashType); ok {
+		target.entity = trashEntities[trashType]
+	}
+	var keys []string
+	if target.keys != nil {
+		keys = target.keys(fc.Args)
+	}
+	snapshot, hasSnapshot := auditSnapshots[operation]
+	if !hasSnapshot && target.entity != "" && len(keys) > 0 {
+		snapshot = func(ctx context.Context, r *Resolver, args map[string]interface{}) (interface{}, error) {
+			return (&queryResolver{r}).Node(ctx, encodeNodeID(target.entity, keys...))
+		}
+	}
+	var before interface{}
+	if snapshot != nil {
+		before, _ = snapshot(ctx, r, fc.Args)
+	}
+
+	result, err := next(ctx)
+
+	after := result
+	if err == nil && snapshot != nil {
+		after, _ = snapshot(ctx, r, fc.Args)
+	}
+	if len(keys) == 0 {
+		keys = resultID(result)
+	}
+	if auditErr := r.writeAuditLog(ctx, operation, target.entity, keys, fc.Args, before, after, err); auditErr != nil {
+		log.Println("audit:", auditErr)
+	}
+	return result, err
+}
+
+// resultID returns the id of a mutation result, for mutations that create the
+// entity they're recorded against.
+func resultID(result interface{}) []string {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(body, &object) != nil || object.ID == "" {
+		return nil
+	}
+	return []string{object.ID}
+}
+
+func (r *Resolver) writeAuditLog(ctx context.Context, operation string, entity string, keys []string, args map[string]interface{}, before interface{}, after interface{}, mutationErr error) error {
+	// a mutation rejected for its viewer is still logged, with the user id
+	// from the token if there is one and no role
+	actorID, _ := ctx.Value("user_id").(string)
+	role := 0
+	if viewer, err := r.getViewer(ctx); err == nil {
+		role = viewer.Role
+	}
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	params := []db.AuditLogSetParam{}
+	if entity != "" {
+		params = append(params, db.AuditLog.Entity.Set(entity))
+	}
+	if len(keys) > 0 {
+		params = append(params, db.AuditLog.EntityID.Set(strings.Join(keys, ":")))
+	}
+	if before != nil {
+		body, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		params = append(params, db.AuditLog.Before.Set(string(body)))
+	}
+	if after != nil && mutationErr == nil {
+		body, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		params = append(params, db.AuditLog.After.Set(string(body)))
+	}
+	if mutationErr != nil {
+		params = append(params, db.AuditLog.Error.Set(mutationErr.Error()))
+	}
+	_, err = r.Client.AuditLog.CreateOne(
+		db.AuditLog.ActorID.Set(actorID),
+		db.AuditLog.Role.Set(role),
+		db.AuditLog.Operation.Set(operation),
+		db.AuditLog.Arguments.Set(string(arguments)),
+		params...,
+	).Exec(ctx)
+	return err
+}
+
+// PurgeAuditLog deletes audit log entries older than the retention period.
+func (r *Resolver) PurgeAuditLog(ctx context.Context) error {
+	retention := r.AuditRetention
+	if retention <= 0 {
+		retention = DefaultAuditRetention
+	}
+	_, err := r.Client.AuditLog.FindMany(
+		db.AuditLog.CreatedAt.Lt(time.Now().Add(-retention)),
+	).Delete().Exec(ctx)
+	return err
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+)
+
+// A catalog course is the permanent subject, such as CSC210, and each Course
+// row is one offering of it in a semester. Offerings are linked to their
+// catalog course so attainment can be followed across semesters.
+
+func catalogCourseModel(catalogCourse db.CatalogCourseModel) *model.CatalogCourse {
+	return &model.CatalogCourse{
+		ID:        catalogCourse.ID,
+		ProgramID: catalogCourse.ProgramID,
+		Code:      catalogCourse.Code,
+		Name:      catalogCourse.Name,
+		Credits:   catalogCourse.Credits,
+	}
+}
+
+func validateCatalogCourseInput(input model.CreateCatalogCourseInput) error {
+	errs := inputErrors{}
+	if input.Code == "" {
+		errs.add("input.code", "code can't be empty")
+	}
+	if input.Credits < 0 {
+		errs.add("input.credits", "credits can't be negative")
+	}
+	return errs.err()
+}
+
+// courseCatalogCourse checks that the catalog course named in a course's
+// input belongs to the course's program.
+func (r *Resolver) courseCatalogCourse(ctx context.Context, input model.CreateCourseInput, programID string) error {
+	if input.CatalogCourseID == nil {
+		return nil
+	}
+	catalogCourse, err := r.Client.CatalogCourse.FindUnique(
+		db.CatalogCourse.ID.Equals(*input.CatalogCourseID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if catalogCourse.ProgramID != programID {
+		return errors.New("catalog course belongs to another program")
+	}
+	return nil
+}
+
+// courseCatalogParams links a course to the catalog course named in its
+// input, see courseCatalogCourse. Without a catalogCourseID the link is left
+// as it is.
+func (r *Resolver) courseCatalogParams(ctx context.Context, input model.CreateCourseInput, programID string) ([]db.CourseSetParam, error) {
+	if input.CatalogCourseID == nil {
+		return nil, nil
+	}
+	if err := r.courseCatalogCourse(ctx, input, programID); err != nil {
+		return nil, err
+	}
+	return []db.CourseSetParam{
+		db.Course.CatalogCourse.Link(
+			db.CatalogCourse.ID.Equals(*input.CatalogCourseID),
+		),
+	}, nil
+}
+
+// offeringAttainment averages, for each PLO, the attainment of the students
+// enrolled in one offering, the same way individualPLOGroupSummary does:
+// each student's score percentages on linked questions are averaged first,
+// weighted by their question and LO links.
+func (r *Resolver) offeringAttainment(ctx context.Context, course db.CourseModel) (*model.CatalogAttainment, error) {
+	enrollments, err := r.Client.Enrollment.FindMany(
+		activeEnrollment(db.Enrollment.CourseID.Equals(course.ID))...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allQuestionResults, err := r.Client.QuestionResult.FindMany(
+		db.QuestionResult.Question.Where(
+			db.Question.Quiz.Where(liveQuizzes(db.Course.ID.Equals(course.ID))...),
+		),
+		enrolledResult(course.ID, nil),
+	).With(
+		db.QuestionResult.Question.Fetch().With(
+			db.Question.Links.Fetch().With(
+				db.QuestionLink.LoLevel.Fetch().With(
+					db.LOlevel.Lo.Fetch().With(
+						db.LO.Links.Fetch().With(
+							db.LOlink.Plo.Fetch(),
+						),
+					),
+				),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	type StudentRecord struct {
+		percentage float64
+		weight     float64
+	}
+	plos := map[string]*model.CatalogPLOAttainment{}
+	ploRecords := map[string]map[string]*StudentRecord{}
+	for _, questionResult := range allQuestionResults {
+		thisPercent := scorePercentage(questionResult.Score, questionResult.Question().MaxScore)
+		for _, qlink := range questionResult.Question().Links() {
+			for _, llink := range qlink.LoLevel().Lo().Links() {
+				ploID := llink.Plo().ID
+				if _, ok := plos[ploID]; !ok {
+					plos[ploID] = &model.CatalogPLOAttainment{
+						PloID: ploID,
+						Title: llink.Plo().Title,
+					}
+					ploRecords[ploID] = map[string]*StudentRecord{}
+				}
+				studentRecord, ok := ploRecords[ploID][questionResult.StudentID]
+				if !ok {
+					studentRecord = &StudentRecord{}
+					ploRecords[ploID][questionResult.StudentID] = studentRecord
+				}
+				weight := linkShare(*questionResult.Question(), qlink) * llink.Weight
+				studentRecord.percentage = weightedMean(studentRecord.percentage, studentRecord.weight, thisPercent, weight)
+				studentRecord.weight += weight
+			}
+		}
+	}
+	attainment := &model.CatalogAttainment{
+		CourseID: course.ID,
+		Semester: course.Semester,
+		Year:     course.Year,
+		Students: len(enrollments),
+		Plos:     []*model.CatalogPLOAttainment{},
+	}
+	for ploID, plo := range plos {
+		var sum float64
+		for _, student := range ploRecords[ploID] {
+			sum += student.percentage
+		}
+		plo.Mean = sum / float64(len(ploRecords[ploID]))
+		attainment.Plos = append(attainment.Plos, plo)
+	}
+	return attainment, nil
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+
+	"github.com/google/uuid"
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+// cloneCourseTx returns the transactions that copy a course's structure into
+// a new course: its LOs with their levels and PLO links and, when asked, its
+// quizzes with their questions, question links and category weights but
+// without results. The source must be loaded with its LOs, live quizzes,
+// category weights and grade scheme. The result maps every copied entity to
+// its new ID; its course only carries the new ID.
+func (r *Resolver) cloneCourseTx(source db.CourseModel, teacherID string, input model.CreateCourseInput, courseParams []db.CourseSetParam, includeQuizzes bool) ([]transaction.Param, *model.CloneCourseResult) {
+	courseID := uuid.New().String()
+	params := append(courseParams,
+		db.Course.Teacher.Link(
+			db.Teacher.ID.Equals(teacherID),
+		),
+		db.Course.ID.Set(courseID),
+	)
+	if input.PloGroupID != "" {
+		params = append(params, db.Course.PloGroup.Link(
+			db.PLOgroup.ID.Equals(input.PloGroupID),
+		))
+	}
+	if scheme, ok := source.GradeScheme(); ok {
+		// a course's own grade scheme stays with it, a program's is shared
+		if _, own := scheme.CourseID(); !own {
+			params = append(params, db.Course.GradeScheme.Link(
+				db.GradeScheme.ID.Equals(scheme.ID),
+			))
+		}
+	}
+	transactions := []transaction.Param{
+		r.Client.Course.CreateOne(
+			db.Course.Name.Set(input.Name),
+			db.Course.Description.Set(input.Description),
+			db.Course.Semester.Set(input.Semester),
+			db.Course.Year.Set(input.Year),
+			db.Course.Program.Link(
+				db.Program.ID.Equals(source.ProgramID),
+			),
+			params...,
+		).Tx(),
+	}
+	result := &model.CloneCourseResult{
+		Course:    &model.Course{ID: courseID},
+		Los:       []*model.ClonedID{},
+		Quizzes:   []*model.ClonedID{},
+		Questions: []*model.ClonedID{},
+	}
+
+	loIDs := map[string]string{}
+	for _, lo := range source.Los() {
+		loID := uuid.New().String()
+		loIDs[lo.ID] = loID
+		result.Los = append(result.Los, &model.ClonedID{OldID: lo.ID, NewID: loID})
+		transactions = append(transactions, r.Client.LO.CreateOne(
+			db.LO.Title.Set(lo.Title),
+			db.LO.Course.Link(
+				db.Course.ID.Equals(courseID),
+			),
+			db.LO.ID.Set(loID),
+		).Tx())
+		for _, level := range lo.Levels() {
+			transactions = append(transactions, r.Client.LOlevel.CreateOne(
+				db.LOlevel.Level.Set(level.Level),
+				db.LOlevel.Description.Set(level.Description),
+				db.LOlevel.Lo.Link(
+					db.LO.ID.Equals(loID),
+				),
+			).Tx())
+		}
+		for _, link := range lo.Links() {
+			transactions = append(transactions, r.Client.LOlink.CreateOne(
+				db.LOlink.Lo.Link(
+					db.LO.ID.Equals(loID),
+				),
+				db.LOlink.Plo.Link(
+					db.PLO.ID.Equals(link.PloID),
+				),
+				linkParams(link)...,
+			).Tx())
+		}
+	}
+	if !includeQuizzes {
+		return transactions, result
+	}
+
+	for _, weight := range source.CategoryWeights() {
+		transactions = append(transactions, r.Client.CategoryWeight.CreateOne(
+			db.CategoryWeight.Course.Link(
+				db.Course.ID.Equals(courseID),
+			),
+			db.CategoryWeight.Category.Set(weight.Category),
+			db.CategoryWeight.Weight.Set(weight.Weight),
+		).Tx())
+	}
+	for _, quiz := range source.Quizzes() {
+		quizID := uuid.New().String()
+		result.Quizzes = append(result.Quizzes, &model.ClonedID{OldID: quiz.ID, NewID: quizID})
+		transactions = append(transactions, r.Client.Quiz.CreateOne(
+			db.Quiz.Name.Set(quiz.Name),
+			db.Quiz.Course.Link(
+				db.Course.ID.Equals(courseID),
+			),
+			db.Quiz.ID.Set(quizID),
+			db.Quiz.Category.Set(quiz.Category),
+			db.Quiz.Weight.Set(quiz.Weight),
+		).Tx())
+		for _, question := range quiz.Questions() {
+			questionID := uuid.New().String()
+			result.Questions = append(result.Questions, &model.ClonedID{OldID: question.ID, NewID: questionID})
+			transactions = append(transactions, r.Client.Question.CreateOne(
+				db.Question.Title.Set(question.Title),
+				db.Question.MaxScore.Set(question.MaxScore),
+				db.Question.Quiz.Link(
+					db.Quiz.ID.Equals(quizID),
+				),
+				db.Question.ID.Set(questionID),
+			).Tx())
+			for _, link := range question.Links() {
+				loID, ok := loIDs[link.LoID]
+				if !ok {
+					continue
+				}
+				transactions = append(transactions, r.Client.QuestionLink.CreateOne(
+					db.QuestionLink.Question.Link(
+						db.Question.ID.Equals(questionID),
+					),
+					db.QuestionLink.LoLevel.Link(
+						db.LOlevel.LoIDLevel(
+							db.LOlevel.LoID.Equals(loID),
+							db.LOlevel.Level.Equals(link.Level),
+						),
+					),
+					db.QuestionLink.Weight.Set(link.Weight),
+				).Tx())
+			}
+		}
+	}
+	return transactions, result
+}
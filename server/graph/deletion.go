@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+)
+
+// The *Impact functions count what deleting an entity takes with it. Links
+// are counted when they cascade away with the entity. Question results and
+// courses are counted when they lose data: the results stop counting towards
+// an outcome, and the courses lose LOs, links or their PLO group.
+
+func (r *queryResolver) ploImpact(ctx context.Context, id string) (*model.DeletionImpact, error) {
+	if _, err := r.Client.PLO.FindUnique(
+		db.PLO.ID.Equals(id),
+	).Exec(ctx); err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	loLinks, err := r.Client.LOlink.FindMany(
+		db.LOlink.PloID.Equals(id),
+	).With(
+		db.LOlink.Lo.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	courses := map[string]bool{}
+	for _, loLink := range loLinks {
+		courses[loLink.Lo().CourseID] = true
+	}
+	questionResults, err := r.Client.QuestionResult.FindMany(
+		db.QuestionResult.Question.Where(
+			db.Question.Links.Some(
+				db.QuestionLink.LoLevel.Where(
+					db.LOlevel.Lo.Where(
+						db.LO.Links.Some(
+							db.LOlink.PloID.Equals(id),
+						),
+					),
+				),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	return &model.DeletionImpact{
+		LoLinks:         len(loLinks),
+		QuestionResults: len(questionResults),
+		Courses:         len(courses),
+	}, nil
+}
+
+func (r *queryResolver) ploGroupImpact(ctx context.Context, id string) (*model.DeletionImpact, error) {
+	if _, err := r.Client.PLOgroup.FindUnique(
+		db.PLOgroup.ID.Equals(id),
+	).Exec(ctx); err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	loLinks, err := r.Client.LOlink.FindMany(
+		db.LOlink.Plo.Where(
+			db.PLO.PloGroupID.Equals(id),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	courses, err := r.Client.Course.FindMany(
+		db.Course.Or(
+			db.Course.PloGroupID.Equals(id),
+			db.Course.Los.Some(
+				db.LO.Links.Some(
+					db.LOlink.Plo.Where(
+						db.PLO.PloGroupID.Equals(id),
+					),
+				),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	questionResults, err := r.Client.QuestionResult.FindMany(
+		db.QuestionResult.Question.Where(
+			db.Question.Links.Some(
+				db.QuestionLink.LoLevel.Where(
+					db.LOlevel.Lo.Where(
+						db.LO.Links.Some(
+							db.LOlink.Plo.Where(
+								db.PLO.PloGroupID.Equals(id),
+							),
+						),
+					),
+				),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	return &model.DeletionImpact{
+		LoLinks:         len(loLinks),
+		QuestionResults: len(questionResults),
+		Courses:         len(courses),
+	}, nil
+}
+
+func (r *queryResolver) loImpact(ctx context.Context, id string) (*model.DeletionImpact, error) {
+	if _, err := r.Client.LO.FindUnique(
+		db.LO.ID.Equals(id),
+	).Exec(ctx); err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	loLinks, err := r.Client.LOlink.FindMany(
+		db.LOlink.LoID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	questionLinks, err := r.Client.QuestionLink.FindMany(
+		db.QuestionLink.LoID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	questionResults, err := r.Client.QuestionResult.FindMany(
+		db.QuestionResult.Question.Where(
+			db.Question.Links.Some(
+				db.QuestionLink.LoID.Equals(id),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	return &model.DeletionImpact{
+		LoLinks:         len(loLinks),
+		QuestionLinks:   len(questionLinks),
+		QuestionResults: len(questionResults),
+		Courses:         1,
+	}, nil
+}
+
+func (r *queryResolver) loLevelImpact(ctx context.Context, id string, level int) (*model.DeletionImpact, error) {
+	if _, err := r.Client.LOlevel.FindUnique(
+		db.LOlevel.LoIDLevel(
+			db.LOlevel.LoID.Equals(id),
+			db.LOlevel.Level.Equals(level),
+		),
+	).Exec(ctx); err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	questionLinks, err := r.Client.QuestionLink.FindMany(
+		db.QuestionLink.LoID.Equals(id),
+		db.QuestionLink.Level.Equals(level),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	questionResults, err := r.Client.QuestionResult.FindMany(
+		db.QuestionResult.Question.Where(
+			db.Question.Links.Some(
+				db.QuestionLink.LoID.Equals(id),
+				db.QuestionLink.Level.Equals(level),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DeletionImpact{}, err
+	}
+	return &model.DeletionImpact{
+		QuestionLinks:   len(questionLinks),
+		QuestionResults: len(questionResults),
+		Courses:         1,
+	}, nil
+}
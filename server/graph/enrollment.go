@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+// A student belongs to a course through an Enrollment. Withdrawn students
+// keep their record, and their results, but no longer count as taking the
+// course. Results can only be recorded for students enrolled in the course;
+// enrolling them is left to the enrollment mutations.
+
+func activeEnrollment(where ...db.EnrollmentWhereParam) []db.EnrollmentWhereParam {
+	return append([]db.EnrollmentWhereParam{
+		db.Enrollment.Not(
+			db.Enrollment.Status.Equals(db.EnrollmentStatus(model.EnrollmentStatusWithdrawn)),
+		),
+	}, where...)
+}
+
+// enrolledResult matches results of students still enrolled in the course,
+// so a withdrawn student drops out of its dashboards. Passing a section
+// narrows it to that section's students.
+func enrolledResult(courseID string, sectionID *string) db.QuestionResultWhereParam {
+	return db.QuestionResult.Student.Where(
+		db.Student.Enrollments.Some(
+			activeEnrollment(
+				db.Enrollment.CourseID.Equals(courseID),
+				db.Enrollment.SectionID.EqualsIfPresent(sectionID),
+			)...,
+		),
+	)
+}
+
+func (r *Resolver) enrollments(ctx context.Context, courseID string, where ...db.EnrollmentWhereParam) ([]*model.Enrollment, error) {
+	allEnrollments, err := r.Client.Enrollment.FindMany(
+		append(where, db.Enrollment.CourseID.Equals(courseID))...,
+	).With(
+		db.Enrollment.Student.Fetch().With(
+			db.Student.User.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.Enrollment{}, err
+	}
+	enrollments := []*model.Enrollment{}
+	for _, enrollment := range allEnrollments {
+		user := enrollment.Student().User()
+		var sectionID *string
+		if id, ok := enrollment.SectionID(); ok {
+			sectionID = &id
+		}
+		enrollments = append(enrollments, &model.Enrollment{
+			CourseID:  enrollment.CourseID,
+			StudentID: enrollment.StudentID,
+			Student: &model.User{
+				ID:      user.ID,
+				Email:   user.Email,
+				Name:    user.Name,
+				Surname: user.Surname,
+			},
+			Status:     model.EnrollmentStatus(enrollment.Status),
+			EnrolledAt: enrollment.EnrolledAt,
+			SectionID:  sectionID,
+		})
+	}
+	return enrollments, nil
+}
+
+// enrolledStudents returns which of the given students are enrolled in the
+// course and haven't withdrawn.
+func (r *Resolver) enrolledStudents(ctx context.Context, courseID string, studentIDs []string) (map[string]bool, error) {
+	enrollments, err := r.Client.Enrollment.FindMany(
+		activeEnrollment(
+			db.Enrollment.CourseID.Equals(courseID),
+			db.Enrollment.StudentID.In(studentIDs),
+		)...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := map[string]bool{}
+	for _, enrollment := range enrollments {
+		enrolled[enrollment.StudentID] = true
+	}
+	return enrolled, nil
+}
+
+// enrollMissing returns the transactions that enroll the given students in a
+// course they have no enrollment record in yet. Withdrawn students are left
+// withdrawn.
+func (r *Resolver) enrollMissing(ctx context.Context, courseID string, studentIDs []string) ([]transaction.Param, error) {
+	if len(studentIDs) == 0 {
+		return nil, nil
+	}
+	enrolled, err := r.Client.Enrollment.FindMany(
+		db.Enrollment.CourseID.Equals(courseID),
+		db.Enrollment.StudentID.In(studentIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, enrollment := range enrolled {
+		seen[enrollment.StudentID] = true
+	}
+	transactions := []transaction.Param{}
+	for _, studentID := range studentIDs {
+		if seen[studentID] {
+			continue
+		}
+		seen[studentID] = true
+		transactions = append(transactions, r.Client.Enrollment.CreateOne(
+			db.Enrollment.Course.Link(
+				db.Course.ID.Equals(courseID),
+			),
+			db.Enrollment.Student.Link(
+				db.Student.ID.Equals(studentID),
+			),
+		).Tx())
+	}
+	return transactions, nil
+}
+
+// BackfillEnrollments enrolls every student who has results in a course but
+// no enrollment record there, which is the case for data recorded before
+// enrollments existed. It goes one course at a time and is safe to run more
+// than once. It is run by the backfill command, not by the server.
+func (r *Resolver) BackfillEnrollments(ctx context.Context) error {
+	courses, err := r.Client.Course.FindMany().Exec(ctx)
+	if err != nil {
+		return err
+	}
+	for _, course := range courses {
+		results, err := r.Client.QuestionResult.FindMany(
+			db.QuestionResult.Question.Where(
+				db.Question.Quiz.Where(
+					db.Quiz.CourseID.Equals(course.ID),
+				),
+			),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		studentIDs := []string{}
+		for _, result := range results {
+			studentIDs = append(studentIDs, result.StudentID)
+		}
+		if len(studentIDs) == 0 {
+			continue
+		}
+		transactions, err := r.enrollMissing(ctx, course.ID, studentIDs)
+		if err != nil {
+			return err
+		}
+		if len(transactions) == 0 {
+			continue
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func enrollmentStatus(status *model.EnrollmentStatus) db.EnrollmentStatus {
+	if status == nil {
+		return db.EnrollmentStatus(model.EnrollmentStatusEnrolled)
+	}
+	return db.EnrollmentStatus(*status)
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// A finalized course is read-only: its LOs, quizzes, links and scores can't
+// change, so the dashboards of past terms stay as they were when grades were
+// submitted. Closing a term finalizes its courses. Only a program chair can
+// unlock a course again, and every finalize and unlock is kept in the
+// course's lock history.
+
+func finalizedError(courseID string) error {
+	return &gqlerror.Error{
+		Message: "the course is finalized, a program chair must unlock it first",
+		Extensions: map[string]interface{}{
+			"code":     "FINALIZED",
+			"courseID": courseID,
+		},
+	}
+}
+
+func isFinalizedError(err error) bool {
+	var gqlErr *gqlerror.Error
+	return errors.As(err, &gqlErr) && gqlErr.Extensions["code"] == "FINALIZED"
+}
+
+func (r *Resolver) checkUnlocked(ctx context.Context, courseID string) error {
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if _, finalized := course.FinalizedAt(); finalized {
+		return finalizedError(courseID)
+	}
+	return nil
+}
+
+// requireUnlockedCourse is requireCourseRole for changes that a finalized
+// course no longer accepts.
+func (r *Resolver) requireUnlockedCourse(ctx context.Context, courseID string, roles []model.CourseStaffRole) error {
+	if err := r.requireCourseRole(ctx, courseID, roles); err != nil {
+		return err
+	}
+	return r.checkUnlocked(ctx, courseID)
+}
+
+// checkPLOUnlocked rejects changes to a PLO whose group is used by a
+// finalized course, since deleting the PLO would take that course's LO links
+// with it.
+func (r *Resolver) checkPLOUnlocked(ctx context.Context, ploID string) error {
+	finalized, err := r.Client.Course.FindFirst(
+		db.Course.PloGroup.Where(
+			db.PLOgroup.Plos.Some(
+				db.PLO.ID.Equals(ploID),
+			),
+		),
+		db.Course.Not(db.Course.FinalizedAt.IsNull()),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return finalizedError(finalized.ID)
+}
+
+// finalizeCourses returns the transactions that finalize the given courses
+// and record it in their lock history.
+func (r *Resolver) finalizeCourses(actorID string, courseIDs []string) []transaction.Param {
+	now := time.Now()
+	transactions := []transaction.Param{}
+	for _, courseID := range courseIDs {
+		transactions = append(transactions,
+			r.Client.Course.FindUnique(
+				db.Course.ID.Equals(courseID),
+			).Update(
+				db.Course.FinalizedAt.Set(now),
+			).Tx(),
+			r.Client.CourseLockEvent.CreateOne(
+				db.CourseLockEvent.Course.Link(
+					db.Course.ID.Equals(courseID),
+				),
+				db.CourseLockEvent.Action.Set(db.CourseLockAction(model.CourseLockActionFinalized)),
+				db.CourseLockEvent.ActorID.Set(actorID),
+			).Tx(),
+		)
+	}
+	return transactions
+}
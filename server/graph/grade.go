@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// A course grade is the weighted mean of a student's quiz percentages. Each
+// quiz belongs to an assessment category, the course weighs the categories
+// against each other, and a quiz's weight is relative to the other quizzes
+// of its category. Categories the course hasn't weighted count with weight
+// 1, and categories without quizzes are left out. A missing result counts
+// as a score of 0.
+
+type gradeBook struct {
+	quizzes        []db.QuizModel
+	maxScores      map[string]decimal.Decimal
+	shares         map[string]float64
+	categoryShares map[model.AssessmentCategory]float64
+}
+
+// newGradeBook works out each quiz's share of the course grade. The quizzes
+// must be loaded with their questions and, to grade students, the questions'
+// results.
+func newGradeBook(quizzes []db.QuizModel, weights []db.CategoryWeightModel) *gradeBook {
+	g := &gradeBook{
+		quizzes:        []db.QuizModel{},
+		maxScores:      map[string]decimal.Decimal{},
+		shares:         map[string]float64{},
+		categoryShares: map[model.AssessmentCategory]float64{},
+	}
+	categoryWeights := map[model.AssessmentCategory]float64{}
+	for _, weight := range weights {
+		categoryWeights[model.AssessmentCategory(weight.Category)] = weight.Weight
+	}
+	quizWeights := map[model.AssessmentCategory]float64{}
+	for _, quiz := range quizzes {
+		maxScore := decimal.Zero
+		for _, question := range quiz.Questions() {
+			maxScore = maxScore.Add(question.MaxScore)
+		}
+		if maxScore.IsZero() {
+			continue
+		}
+		g.quizzes = append(g.quizzes, quiz)
+		g.maxScores[quiz.ID] = maxScore
+		quizWeights[model.AssessmentCategory(quiz.Category)] += quiz.Weight
+	}
+	var total float64
+	for category, quizWeight := range quizWeights {
+		if quizWeight == 0 {
+			continue
+		}
+		weight, ok := categoryWeights[category]
+		if !ok {
+			weight = 1
+		}
+		g.categoryShares[category] = weight
+		total += weight
+	}
+	for category, weight := range g.categoryShares {
+		if total == 0 {
+			g.categoryShares[category] = 0
+			continue
+		}
+		g.categoryShares[category] = weight / total
+	}
+	for _, quiz := range g.quizzes {
+		category := model.AssessmentCategory(quiz.Category)
+		if quizWeights[category] > 0 {
+			g.shares[quiz.ID] = g.categoryShares[category] * quiz.Weight / quizWeights[category]
+		}
+	}
+	return g
+}
+
+func (r *Resolver) gradeBook(ctx context.Context, courseID string, results ...db.QuestionResultWhereParam) (*gradeBook, error) {
+	quizzes, err := r.Client.Quiz.FindMany(
+		append(liveQuizzes(), db.Quiz.CourseID.Equals(courseID))...,
+	).With(
+		db.Quiz.Questions.Fetch().With(
+			db.Question.Results.Fetch(results...),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	weights, err := r.Client.CategoryWeight.FindMany(
+		db.CategoryWeight.CourseID.Equals(courseID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newGradeBook(quizzes, weights), nil
+}
+
+// requireQuizReader lets the course's graders and its students read its
+// quizzes. Students only get their own results, see visibleResults.
+func (r *Resolver) requireQuizReader(ctx context.Context, viewer *viewer, courseID string) error {
+	if viewer.IsTeacher {
+		return r.requireCourseRole(ctx, courseID, courseGraders)
+	}
+	_, err := r.Client.Course.FindFirst(
+		append(append(viewer.takenCourse(), liveCourses()...), db.Course.ID.Equals(courseID))...,
+	).Exec(ctx)
+	return err
+}
+
+// courseResults returns the filters for the results of a course that the
+// viewer may read, after checking that they may read the course's quizzes at
+// all.
+func (r *Resolver) courseResults(ctx context.Context, courseID string) ([]db.QuestionResultWhereParam, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.requireQuizReader(ctx, viewer, courseID); err != nil {
+		return nil, err
+	}
+	return viewer.visibleResults(), nil
+}
+
+// requireGradeViewer lets the course staff read any student's grade, and a
+// student read their own.
+func (r *Resolver) requireGradeViewer(ctx context.Context, courseID string, studentID string) error {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return err
+	}
+	if !viewer.IsTeacher && viewer.ID == studentID {
+		return nil
+	}
+	return r.requireCourseRole(ctx, courseID, courseGraders)
+}
+
+// percentage returns the part of a quiz's max score the student got.
+func (g *gradeBook) percentage(quiz db.QuizModel, studentID string) float64 {
+	score := decimal.Zero
+	for _, question := range quiz.Questions() {
+		for _, result := range question.Results() {
+			if result.StudentID == studentID {
+				score = score.Add(result.Score)
+			}
+		}
+	}
+	return scorePercentage(score, g.maxScores[quiz.ID])
+}
+
+func (g *gradeBook) grade(courseID string, studentID string) *model.CourseGrade {
+	grade := &model.CourseGrade{
+		CourseID:   courseID,
+		StudentID:  studentID,
+		Categories: []*model.CourseGradeCategory{},
+	}
+	categoryPercentages := map[model.AssessmentCategory]float64{}
+	for _, quiz := range g.quizzes {
+		share := g.shares[quiz.ID]
+		if share == 0 {
+			continue
+		}
+		percentage := g.percentage(quiz, studentID)
+		category := model.AssessmentCategory(quiz.Category)
+		grade.Percentage += share * percentage
+		categoryPercentages[category] += share / g.categoryShares[category] * percentage
+	}
+	for _, category := range model.AllAssessmentCategory {
+		share, ok := g.categoryShares[category]
+		if !ok {
+			continue
+		}
+		grade.Categories = append(grade.Categories, &model.CourseGradeCategory{
+			Category:   category,
+			Share:      share,
+			Percentage: categoryPercentages[category],
+		})
+	}
+	return grade
+}
+
+func validateGradeWeight(path string, weight *float64, errs *inputErrors) {
+	if weight != nil && *weight < 0 {
+		errs.add(path, "weight can't be negative")
+	}
+}
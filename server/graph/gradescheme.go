@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+	"github.com/shopspring/decimal"
+)
+
+// A grade scheme turns a course grade into a letter. A program keeps named
+// schemes its courses can share, and a course can also have a scheme of its
+// own. The course grade is rounded to the scheme's precision, in percentage
+// points, and gets the letter of the highest boundary it reaches. Courses
+// without a scheme use defaultGradeBoundaries.
+
+var defaultGradeBoundaries = []*model.GradeBoundary{
+	{Letter: "A", MinPercentage: 80},
+	{Letter: "B+", MinPercentage: 75},
+	{Letter: "B", MinPercentage: 70},
+	{Letter: "C+", MinPercentage: 65},
+	{Letter: "C", MinPercentage: 60},
+	{Letter: "D+", MinPercentage: 55},
+	{Letter: "D", MinPercentage: 50},
+	{Letter: "F", MinPercentage: 0},
+}
+
+// gradeNoisePlaces is how many decimal places of percentage points a course
+// grade keeps before it's rounded.
+const gradeNoisePlaces = 8
+
+func defaultGradeScheme() *model.GradeScheme {
+	return &model.GradeScheme{
+		Rounding:   model.GradeRoundingNone,
+		Boundaries: defaultGradeBoundaries,
+	}
+}
+
+// gradeSchemeModel needs the scheme loaded with its boundaries. The
+// boundaries are sorted from the highest down.
+func gradeSchemeModel(scheme db.GradeSchemeModel) *model.GradeScheme {
+	boundaries := []*model.GradeBoundary{}
+	for _, boundary := range scheme.Boundaries() {
+		boundaries = append(boundaries, &model.GradeBoundary{
+			Letter:        boundary.Letter,
+			MinPercentage: boundary.MinPercentage,
+		})
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].MinPercentage > boundaries[j].MinPercentage
+	})
+	courseID, ok := scheme.CourseID()
+	gradeScheme := &model.GradeScheme{
+		ID:         scheme.ID,
+		ProgramID:  scheme.ProgramID,
+		Name:       scheme.Name,
+		Rounding:   model.GradeRounding(scheme.Rounding),
+		Precision:  scheme.Precision,
+		Boundaries: boundaries,
+	}
+	if ok {
+		gradeScheme.CourseID = &courseID
+	}
+	return gradeScheme
+}
+
+func (r *Resolver) gradeScheme(ctx context.Context, id string) (*model.GradeScheme, error) {
+	scheme, err := r.Client.GradeScheme.FindUnique(
+		db.GradeScheme.ID.Equals(id),
+	).With(
+		db.GradeScheme.Boundaries.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return &model.GradeScheme{}, err
+	}
+	return gradeSchemeModel(*scheme), nil
+}
+
+func validateGradeSchemeInput(input model.GradeSchemeInput) error {
+	errs := inputErrors{}
+	if input.Name == "" {
+		errs.add("input.name", "name can't be empty")
+	}
+	if input.Precision != nil && (*input.Precision < 0 || *input.Precision > 4) {
+		errs.add("input.precision", "precision must be between 0 and 4")
+	}
+	if len(input.Boundaries) == 0 {
+		errs.add("input.boundaries", "a grade scheme needs at least one boundary")
+	}
+	letters := map[string]bool{}
+	hasZero := false
+	for i, boundary := range input.Boundaries {
+		path := fmt.Sprintf("input.boundaries[%d]", i)
+		if boundary.Letter == "" {
+			errs.add(path+".letter", "letter can't be empty")
+		} else if letters[boundary.Letter] {
+			errs.add(path+".letter", "%s is used twice", boundary.Letter)
+		}
+		letters[boundary.Letter] = true
+		if boundary.MinPercentage < 0 || boundary.MinPercentage > 100 {
+			errs.add(path+".minPercentage", "minPercentage must be between 0 and 100")
+		}
+		if boundary.MinPercentage == 0 {
+			hasZero = true
+		}
+	}
+	if len(input.Boundaries) > 0 && !hasZero {
+		errs.add("input.boundaries", "the lowest boundary must start at 0")
+	}
+	return errs.err()
+}
+
+// gradeSchemeTx returns the transactions that write the input into a grade
+// scheme, replacing its boundaries. An existing scheme keeps its program and
+// course.
+func (r *Resolver) gradeSchemeTx(id string, programID string, courseID *string, input model.GradeSchemeInput, exists bool) []transaction.Param {
+	params := []db.GradeSchemeSetParam{
+		db.GradeScheme.Precision.SetIfPresent(input.Precision),
+	}
+	if input.Rounding != nil {
+		params = append(params, db.GradeScheme.Rounding.Set(db.GradeRounding(*input.Rounding)))
+	}
+	transactions := []transaction.Param{}
+	if exists {
+		transactions = append(transactions,
+			r.Client.GradeScheme.FindUnique(
+				db.GradeScheme.ID.Equals(id),
+			).Update(
+				append(params, db.GradeScheme.Name.Set(input.Name))...,
+			).Tx(),
+			r.Client.GradeBoundary.FindMany(
+				db.GradeBoundary.SchemeID.Equals(id),
+			).Delete().Tx(),
+		)
+	} else {
+		params = append(params, db.GradeScheme.ID.Set(id))
+		if courseID != nil {
+			params = append(params, db.GradeScheme.Course.Link(
+				db.Course.ID.Equals(*courseID),
+			))
+		}
+		transactions = append(transactions, r.Client.GradeScheme.CreateOne(
+			db.GradeScheme.Name.Set(input.Name),
+			db.GradeScheme.Program.Link(
+				db.Program.ID.Equals(programID),
+			),
+			params...,
+		).Tx())
+	}
+	for _, boundary := range input.Boundaries {
+		transactions = append(transactions, r.Client.GradeBoundary.CreateOne(
+			db.GradeBoundary.Scheme.Link(
+				db.GradeScheme.ID.Equals(id),
+			),
+			db.GradeBoundary.Letter.Set(boundary.Letter),
+			db.GradeBoundary.MinPercentage.Set(boundary.MinPercentage),
+		).Tx())
+	}
+	return transactions
+}
+
+// letterGrade rounds a course grade to the scheme's precision, in percentage
+// points, and returns it with its letter. The float noise of the weighted
+// mean is dropped first, so 79.5 isn't rounded as 79.49999999999999.
+func letterGrade(scheme *model.GradeScheme, percentage float64) (float64, string) {
+	points := decimal.NewFromFloat(percentage * 100).Round(gradeNoisePlaces)
+	precision := int32(scheme.Precision)
+	switch scheme.Rounding {
+	case model.GradeRoundingHalfUp:
+		points = points.Round(precision)
+	case model.GradeRoundingUp:
+		points = points.RoundCeil(precision)
+	case model.GradeRoundingDown:
+		points = points.RoundFloor(precision)
+	}
+	for _, boundary := range scheme.Boundaries {
+		if points.GreaterThanOrEqual(decimal.NewFromFloat(boundary.MinPercentage)) {
+			return points.InexactFloat64(), boundary.Letter
+		}
+	}
+	return points.InexactFloat64(), scheme.Boundaries[len(scheme.Boundaries)-1].Letter
+}
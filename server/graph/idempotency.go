@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"api/server/db"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// DefaultIdempotencyWindow is how long a key is remembered when
+// Resolver.IdempotencyWindow isn't set.
+const DefaultIdempotencyWindow = 24 * time.Hour
+
+// idempotencyKey returns the key sent as the mutation argument, or else the
+// one from the Idempotency-Key header. The header covers every mutation of
+// the request, so its key is scoped to the mutation's field alias. An empty
+// key means the mutation isn't idempotent.
+func idempotencyKey(ctx context.Context, key *string) string {
+	if key != nil && *key != "" {
+		return *key
+	}
+	header, _ := ctx.Value("idempotency_key").(string)
+	if header == "" {
+		return ""
+	}
+	if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Field.Field != nil {
+		return header + ":" + fc.Field.Alias
+	}
+	return header
+}
+
+// idempotent runs create at most once per key. The first call stores what
+// create put into result; a replay with the same operation and payload
+// decodes the stored result into result instead of running create again.
+// A key can't be reused for a different request until the window is over.
+func (r *Resolver) idempotent(ctx context.Context, key *string, operation string, payload interface{}, result interface{}, create func() error) error {
+	idempotencyKey := idempotencyKey(ctx, key)
+	if idempotencyKey == "" {
+		return create()
+	}
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return errors.New("user not found")
+	}
+	window := r.IdempotencyWindow
+	if window <= 0 {
+		window = DefaultIdempotencyWindow
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	if _, err := r.Client.IdempotencyKey.FindMany(
+		db.IdempotencyKey.CreatedAt.Lt(time.Now().Add(-window)),
+	).Delete().Exec(ctx); err != nil {
+		return err
+	}
+	// creating the row first reserves the key, so a retry that arrives while
+	// the first request is still running can't create a second copy
+	if _, err := r.Client.IdempotencyKey.CreateOne(
+		db.IdempotencyKey.UserID.Set(userID),
+		db.IdempotencyKey.Key.Set(idempotencyKey),
+		db.IdempotencyKey.Operation.Set(operation),
+		db.IdempotencyKey.Payload.Set(hash),
+	).Exec(ctx); err != nil {
+		stored, findErr := r.Client.IdempotencyKey.FindUnique(
+			db.IdempotencyKey.UserIDKey(
+				db.IdempotencyKey.UserID.Equals(userID),
+				db.IdempotencyKey.Key.Equals(idempotencyKey),
+			),
+		).Exec(ctx)
+		if findErr != nil {
+			return err
+		}
+		if stored.Operation != operation || stored.Payload != hash {
+			return errors.New("idempotency key was already used for a different request")
+		}
+		response, ok := stored.Response()
+		if !ok {
+			return errors.New("a request with this idempotency key is still in progress")
+		}
+		return json.Unmarshal([]byte(response), result)
+	}
+
+	unique := db.IdempotencyKey.UserIDKey(
+		db.IdempotencyKey.UserID.Equals(userID),
+		db.IdempotencyKey.Key.Equals(idempotencyKey),
+	)
+	// release the key on any failure, so the client can retry instead of
+	// waiting out the window
+	release := func() {
+		if _, err := r.Client.IdempotencyKey.FindUnique(unique).Delete().Exec(ctx); err != nil {
+			log.Println("idempotency:", err)
+		}
+	}
+	if err := create(); err != nil {
+		release()
+		return err
+	}
+	response, err := json.Marshal(result)
+	if err != nil {
+		release()
+		return err
+	}
+	if _, err := r.Client.IdempotencyKey.FindUnique(unique).Update(
+		db.IdempotencyKey.Response.Set(string(response)),
+	).Exec(ctx); err != nil {
+		release()
+		return err
+	}
+	return nil
+}
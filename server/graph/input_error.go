@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// inputErrors collects the problems found in the rows of a bulk input, so a
+// mutation can reject the whole request at once and tell the client which
+// rows to fix. Each path points into the mutation arguments, for example
+// "input.questions[1].results[0]".
+type inputErrors []inputError
+
+type inputError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *inputErrors) add(path string, format string, args ...interface{}) {
+	*e = append(*e, inputError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns nil when no row was rejected. Otherwise the rows are listed in
+// the message and, for clients that want to highlight them, in the
+// invalidRows extension.
+func (e inputErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("%s: %s", e[0].Path, e[0].Message)
+	if len(e) > 1 {
+		message = fmt.Sprintf("%s (and %d more invalid rows)", message, len(e)-1)
+	}
+	return &gqlerror.Error{
+		Message: message,
+		Extensions: map[string]interface{}{
+			"invalidRows": e,
+		},
+	}
+}
+
+// isUniqueConstraintError reports whether a write failed because a row with
+// the same unique key already exists. The Prisma client only passes on the
+// query engine's message, so this matches its text.
+func isUniqueConstraintError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "Unique constraint failed")
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shopspring/decimal"
+)
+
+// Decimal is sent as a JSON number written out in full, so a score of 7.5
+// reaches the client as 7.5 rather than as a rounded float. It's read from a
+// number or a string.
+
+func MarshalDecimal(d decimal.Decimal) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		io.WriteString(w, d.String())
+	})
+}
+
+func UnmarshalDecimal(v interface{}) (decimal.Decimal, error) {
+	switch v := v.(type) {
+	case string:
+		return decimal.NewFromString(v)
+	case json.Number:
+		return decimal.NewFromString(string(v))
+	case int:
+		return decimal.NewFromInt(int64(v)), nil
+	case int64:
+		return decimal.NewFromInt(v), nil
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	default:
+		return decimal.Decimal{}, fmt.Errorf("%T is not a decimal", v)
+	}
+}
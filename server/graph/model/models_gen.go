@@ -3,26 +3,175 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+type Node interface {
+	IsNode()
+}
+
+type SearchResult interface {
+	IsSearchResult()
+}
+
+type AddQuestionResult struct {
+	ID string `json:"id"`
+}
+
+type AuditLogEntry struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	ActorID   string    `json:"actorID"`
+	Role      int       `json:"role"`
+	Operation string    `json:"operation"`
+	Entity    *string   `json:"entity"`
+	EntityID  *string   `json:"entityID"`
+	Arguments string    `json:"arguments"`
+	Before    *string   `json:"before"`
+	After     *string   `json:"after"`
+	Error     *string   `json:"error"`
+}
+
+type AuditLogFilter struct {
+	ActorID   *string    `json:"actorID"`
+	Operation *string    `json:"operation"`
+	Entity    *string    `json:"entity"`
+	EntityID  *string    `json:"entityID"`
+	Since     *time.Time `json:"since"`
+	Until     *time.Time `json:"until"`
+	Limit     *int       `json:"limit"`
+}
+
+type CatalogAttainment struct {
+	CourseID string                  `json:"courseID"`
+	Semester int                     `json:"semester"`
+	Year     int                     `json:"year"`
+	Students int                     `json:"students"`
+	Plos     []*CatalogPLOAttainment `json:"plos"`
+}
+
+type CatalogCourse struct {
+	ID         string               `json:"id"`
+	ProgramID  string               `json:"programID"`
+	Code       string               `json:"code"`
+	Name       string               `json:"name"`
+	Credits    int                  `json:"credits"`
+	Offerings  []*Course            `json:"offerings"`
+	Attainment []*CatalogAttainment `json:"attainment"`
+}
+
+type CatalogPLOAttainment struct {
+	PloID string  `json:"ploID"`
+	Title string  `json:"title"`
+	Mean  float64 `json:"mean"`
+}
+
+type CategoryWeight struct {
+	Category AssessmentCategory `json:"category"`
+	Weight   float64            `json:"weight"`
+}
+
+type CategoryWeightInput struct {
+	Category AssessmentCategory `json:"category"`
+	Weight   float64            `json:"weight"`
+}
+
+type CloneCourseOptions struct {
+	Name           *string `json:"name"`
+	TermID         *string `json:"termID"`
+	IncludeQuizzes *bool   `json:"includeQuizzes"`
+}
+
+type CloneCourseResult struct {
+	Course    *Course     `json:"course"`
+	Los       []*ClonedID `json:"los"`
+	Quizzes   []*ClonedID `json:"quizzes"`
+	Questions []*ClonedID `json:"questions"`
+}
+
+type ClonedID struct {
+	OldID string `json:"oldID"`
+	NewID string `json:"newID"`
+}
+
 type Course struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Semester    int    `json:"semester"`
-	Year        int    `json:"year"`
-	PloGroupID  string `json:"ploGroupID"`
-	ProgramID   string `json:"programID"`
-	TeacherID   string `json:"teacherID"`
+	NodeID          string             `json:"id"`
+	ID              string             `json:"databaseID"`
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	Semester        int                `json:"semester"`
+	Year            int                `json:"year"`
+	PloGroupID      string             `json:"ploGroupID"`
+	ProgramID       string             `json:"programID"`
+	TeacherID       string             `json:"teacherID"`
+	Version         int                `json:"version"`
+	Staff           []*CourseStaff     `json:"staff"`
+	Term            *Term              `json:"term"`
+	CatalogCourse   *CatalogCourse     `json:"catalogCourse"`
+	FinalizedAt     *time.Time         `json:"finalizedAt"`
+	LockHistory     []*CourseLockEvent `json:"lockHistory"`
+	CategoryWeights []*CategoryWeight  `json:"categoryWeights"`
+	GradeScheme     *GradeScheme       `json:"gradeScheme"`
+	Sections        []*Section         `json:"sections"`
+}
+
+func (Course) IsNode()         {}
+func (Course) IsSearchResult() {}
+
+type CourseGrade struct {
+	CourseID   string                 `json:"courseID"`
+	StudentID  string                 `json:"studentID"`
+	Percentage float64                `json:"percentage"`
+	Categories []*CourseGradeCategory `json:"categories"`
+}
+
+type CourseGradeCategory struct {
+	Category   AssessmentCategory `json:"category"`
+	Share      float64            `json:"share"`
+	Percentage float64            `json:"percentage"`
+}
+
+type CourseGrades struct {
+	CourseID     string                `json:"courseID"`
+	Scheme       *GradeScheme          `json:"scheme"`
+	Boundaries   []*GradeBoundary      `json:"boundaries"`
+	Students     []*StudentLetterGrade `json:"students"`
+	Distribution []*GradeCount         `json:"distribution"`
+}
+
+type CourseLockEvent struct {
+	Action    CourseLockAction `json:"action"`
+	ActorID   string           `json:"actorID"`
+	Reason    *string          `json:"reason"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+type CourseStaff struct {
+	CourseID  string          `json:"courseID"`
+	TeacherID string          `json:"teacherID"`
+	Teacher   *User           `json:"teacher"`
+	Role      CourseStaffRole `json:"role"`
+}
+
+type CreateCatalogCourseInput struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Credits int    `json:"credits"`
 }
 
 type CreateCourseInput struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Semester    int    `json:"semester"`
-	Year        int    `json:"year"`
-	PloGroupID  string `json:"ploGroupID"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	Semester        int     `json:"semester"`
+	Year            int     `json:"year"`
+	PloGroupID      string  `json:"ploGroupID"`
+	TermID          *string `json:"termID"`
+	CatalogCourseID *string `json:"catalogCourseID"`
 }
 
 type CreateLOInput struct {
@@ -37,8 +186,10 @@ type CreateLOLevelInput struct {
 }
 
 type CreateLOLinkResult struct {
-	LoID  string `json:"loID"`
-	PloID string `json:"ploID"`
+	LoID         string           `json:"loID"`
+	PloID        string           `json:"ploID"`
+	Weight       float64          `json:"weight"`
+	Contribution *PLOContribution `json:"contribution"`
 }
 
 type CreateLOResult struct {
@@ -67,29 +218,33 @@ type CreateProgramInput struct {
 
 type CreateQuestionInput struct {
 	Title    string                       `json:"title"`
-	MaxScore int                          `json:"maxScore"`
+	MaxScore decimal.Decimal              `json:"maxScore"`
 	Results  []*CreateQuestionResultInput `json:"results"`
 }
 
 type CreateQuestionLinkInput struct {
-	QuestionID string `json:"questionID"`
-	LoID       string `json:"loID"`
-	Level      int    `json:"level"`
+	QuestionID string   `json:"questionID"`
+	LoID       string   `json:"loID"`
+	Level      int      `json:"level"`
+	Weight     *float64 `json:"weight"`
 }
 
 type CreateQuestionLinkResult struct {
-	QuestionID string `json:"questionID"`
-	LoID       string `json:"loID"`
+	QuestionID string  `json:"questionID"`
+	LoID       string  `json:"loID"`
+	Weight     float64 `json:"weight"`
 }
 
 type CreateQuestionResultInput struct {
-	StudentID string `json:"studentID"`
-	Score     int    `json:"score"`
+	StudentID string          `json:"studentID"`
+	Score     decimal.Decimal `json:"score"`
 }
 
 type CreateQuizInput struct {
 	Name      string                 `json:"name"`
 	CreatedAt time.Time              `json:"createdAt"`
+	Category  *AssessmentCategory    `json:"category"`
+	Weight    *float64               `json:"weight"`
 	Questions []*CreateQuestionInput `json:"questions"`
 }
 
@@ -108,6 +263,22 @@ type CreateStudentResult struct {
 	ID string `json:"id"`
 }
 
+type CreateTeacherInput struct {
+	ID      string `json:"id"`
+	Email   string `json:"email"`
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+	Role    int    `json:"role"`
+}
+
+type CreateTermInput struct {
+	Code      string    `json:"code"`
+	Semester  int       `json:"semester"`
+	Year      int       `json:"year"`
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+}
+
 type DashboardFlat struct {
 	Students  []*User                  `json:"students"`
 	Plos      []*Plo                   `json:"plos"`
@@ -116,16 +287,25 @@ type DashboardFlat struct {
 }
 
 type DashboardFlatQuestion struct {
-	Title      string                         `json:"title"`
-	MaxScore   int                            `json:"maxScore"`
-	LinkedPLOs []string                       `json:"linkedPLOs"`
-	LinkedLOs  []string                       `json:"linkedLOs"`
-	Results    []*DashboardFlatQuestionResult `json:"results"`
+	Title          string                            `json:"title"`
+	MaxScore       decimal.Decimal                   `json:"maxScore"`
+	LinkedPLOs     []string                          `json:"linkedPLOs"`
+	LinkedLOs      []string                          `json:"linkedLOs"`
+	LinkedLOLevels []*LOLevel                        `json:"linkedLOLevels"`
+	LinkShares     []*DashboardFlatQuestionLinkShare `json:"linkShares"`
+	Results        []*DashboardFlatQuestionResult    `json:"results"`
+}
+
+type DashboardFlatQuestionLinkShare struct {
+	LoID  string  `json:"loID"`
+	Level int     `json:"level"`
+	Share float64 `json:"share"`
 }
 
 type DashboardFlatQuestionResult struct {
-	StudentID    string `json:"studentID"`
-	StudentScore int    `json:"studentScore"`
+	StudentID    string          `json:"studentID"`
+	SectionID    *string         `json:"sectionID"`
+	StudentScore decimal.Decimal `json:"studentScore"`
 }
 
 type DashboardIndividual struct {
@@ -139,6 +319,7 @@ type DashboardIndividualCourse struct {
 	Year     int                              `json:"year"`
 	Los      []*DashboardIndividualCourseLo   `json:"los"`
 	Quizzes  []*DashboardIndividualCourseQuiz `json:"quizzes"`
+	Grade    float64                          `json:"grade"`
 }
 
 type DashboardIndividualCourseLo struct {
@@ -154,11 +335,14 @@ type DashboardIndividualCourseLOLevel struct {
 }
 
 type DashboardIndividualCourseQuiz struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	MaxScore     int      `json:"maxScore"`
-	StudentScore int      `json:"studentScore"`
-	Los          []string `json:"los"`
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	MaxScore     decimal.Decimal    `json:"maxScore"`
+	StudentScore decimal.Decimal    `json:"studentScore"`
+	Category     AssessmentCategory `json:"category"`
+	Share        float64            `json:"share"`
+	Los          []string           `json:"los"`
+	LoLevels     []*LOLevel         `json:"loLevels"`
 }
 
 type DashboardIndividualPlo struct {
@@ -198,14 +382,18 @@ type DashboardPLOSummary struct {
 
 type DashboardResult struct {
 	QuizName string                `json:"quizName"`
-	MaxScore int                   `json:"maxScore"`
+	MaxScore decimal.Decimal       `json:"maxScore"`
+	Category AssessmentCategory    `json:"category"`
+	Share    float64               `json:"share"`
 	Results  []*DashboardResultSub `json:"results"`
 }
 
 type DashboardResultSub struct {
-	StudentID    string `json:"studentID"`
-	StudentName  string `json:"studentName"`
-	StudentScore int    `json:"studentScore"`
+	StudentID     string          `json:"studentID"`
+	SectionID     *string         `json:"sectionID"`
+	StudentName   string          `json:"studentName"`
+	StudentScore  decimal.Decimal `json:"studentScore"`
+	WeightedScore float64         `json:"weightedScore"`
 }
 
 type DeleteCourseResult struct {
@@ -236,87 +424,321 @@ type DeleteQuestionLinkResult struct {
 	LoID       string `json:"loID"`
 }
 
+type DeleteQuestionResult struct {
+	ID string `json:"id"`
+}
+
 type DeleteQuizResult struct {
 	ID string `json:"id"`
 }
 
+type DeleteSectionResult struct {
+	ID string `json:"id"`
+}
+
+type DeletionImpact struct {
+	LoLinks         int `json:"loLinks"`
+	QuestionLinks   int `json:"questionLinks"`
+	QuestionResults int `json:"questionResults"`
+	Courses         int `json:"courses"`
+}
+
 type EditLOLevelResult struct {
 	ID    string `json:"id"`
 	Level int    `json:"level"`
 }
 
 type EditLOResult struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type EditQuestionInput struct {
+	Title    string          `json:"title"`
+	MaxScore decimal.Decimal `json:"maxScore"`
+}
+
+type EditQuestionResult struct {
 	ID string `json:"id"`
 }
 
 type EditQuizResult struct {
-	ID string `json:"id"`
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type EditTeacherInput struct {
+	Email   string `json:"email"`
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+}
+
+type Enrollment struct {
+	CourseID   string           `json:"courseID"`
+	StudentID  string           `json:"studentID"`
+	Student    *User            `json:"student"`
+	Status     EnrollmentStatus `json:"status"`
+	EnrolledAt time.Time        `json:"enrolledAt"`
+	SectionID  *string          `json:"sectionID"`
+}
+
+type GradeBoundary struct {
+	Letter        string  `json:"letter"`
+	MinPercentage float64 `json:"minPercentage"`
+}
+
+type GradeBoundaryInput struct {
+	Letter        string  `json:"letter"`
+	MinPercentage float64 `json:"minPercentage"`
+}
+
+type GradeCount struct {
+	Letter string `json:"letter"`
+	Count  int    `json:"count"`
+}
+
+type GradeScheme struct {
+	ID         string           `json:"id"`
+	ProgramID  string           `json:"programID"`
+	CourseID   *string          `json:"courseID"`
+	Name       string           `json:"name"`
+	Rounding   GradeRounding    `json:"rounding"`
+	Precision  int              `json:"precision"`
+	Boundaries []*GradeBoundary `json:"boundaries"`
+}
+
+type GradeSchemeInput struct {
+	Name       string                `json:"name"`
+	Rounding   *GradeRounding        `json:"rounding"`
+	Precision  *int                  `json:"precision"`
+	Boundaries []*GradeBoundaryInput `json:"boundaries"`
 }
 
 type Lo struct {
-	ID       string     `json:"id"`
-	Title    string     `json:"title"`
-	Levels   []*LOLevel `json:"levels"`
-	PloLinks []*Plo     `json:"ploLinks"`
+	NodeID   string       `json:"id"`
+	ID       string       `json:"databaseID"`
+	Title    string       `json:"title"`
+	Levels   []*LOLevel   `json:"levels"`
+	PloLinks []*LOPLOLink `json:"ploLinks"`
+	Version  int          `json:"version"`
 }
 
+func (Lo) IsNode()         {}
+func (Lo) IsSearchResult() {}
+
 type LOLevel struct {
+	NodeID      string `json:"id"`
+	LoID        string `json:"loID"`
 	Level       int    `json:"level"`
 	Description string `json:"description"`
 }
 
+func (LOLevel) IsNode() {}
+
+type LOPLOLink struct {
+	ID           string           `json:"id"`
+	Title        string           `json:"title"`
+	Description  string           `json:"description"`
+	PloGroupID   string           `json:"ploGroupID"`
+	Version      int              `json:"version"`
+	Weight       float64          `json:"weight"`
+	Contribution *PLOContribution `json:"contribution"`
+}
+
 type Plo struct {
-	ID          string `json:"id"`
+	NodeID      string `json:"id"`
+	ID          string `json:"databaseID"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	PloGroupID  string `json:"ploGroupID"`
+	Version     int    `json:"version"`
 }
 
+func (Plo) IsNode()         {}
+func (Plo) IsSearchResult() {}
+
 type PLOGroup struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	NodeID       string     `json:"id"`
+	ID           string     `json:"databaseID"`
+	Name         string     `json:"name"`
+	Revision     int        `json:"revision"`
+	PreviousID   *string    `json:"previousID"`
+	SupersededAt *time.Time `json:"supersededAt"`
+}
+
+func (PLOGroup) IsNode() {}
+
+type PLOGroupRevision struct {
+	PloGroup *PLOGroup     `json:"ploGroup"`
+	Mappings []*PLOMapping `json:"mappings"`
+}
+
+type PLOMapping struct {
+	OldPloid string `json:"oldPLOID"`
+	NewPloid string `json:"newPLOID"`
+}
+
+type PLORemapRule struct {
+	OldPloid string `json:"oldPLOID"`
+	NewPloid string `json:"newPLOID"`
 }
 
 type Program struct {
-	ID          string `json:"id"`
+	NodeID      string `json:"id"`
+	ID          string `json:"databaseID"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	TeacherID   string `json:"teacherID"`
+	Version     int    `json:"version"`
 }
 
+func (Program) IsNode()         {}
+func (Program) IsSearchResult() {}
+
 type Question struct {
-	ID       string            `json:"id"`
+	NodeID   string            `json:"id"`
+	ID       string            `json:"databaseID"`
 	Title    string            `json:"title"`
-	MaxScore int               `json:"maxScore"`
+	MaxScore decimal.Decimal   `json:"maxScore"`
 	Results  []*QuestionResult `json:"results"`
 	LoLinks  []*QuestionLink   `json:"loLinks"`
 }
 
+func (Question) IsNode() {}
+
 type QuestionLink struct {
-	LoID        string `json:"loID"`
-	Level       int    `json:"level"`
-	Description string `json:"description"`
+	NodeID      string  `json:"id"`
+	QuestionID  string  `json:"questionID"`
+	LoID        string  `json:"loID"`
+	Level       int     `json:"level"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight"`
 }
 
+func (QuestionLink) IsNode() {}
+
 type QuestionResult struct {
-	StudentID string `json:"studentID"`
-	Score     int    `json:"score"`
+	NodeID     string          `json:"id"`
+	QuestionID string          `json:"questionID"`
+	StudentID  string          `json:"studentID"`
+	Score      decimal.Decimal `json:"score"`
+}
+
+func (QuestionResult) IsNode() {}
+
+type QuestionResultCell struct {
+	StudentID  string               `json:"studentID"`
+	QuestionID string               `json:"questionID"`
+	Status     QuestionResultStatus `json:"status"`
+	Message    *string              `json:"message"`
+}
+
+type QuestionResultEntryInput struct {
+	StudentID  string           `json:"studentID"`
+	QuestionID string           `json:"questionID"`
+	Score      *decimal.Decimal `json:"score"`
 }
 
 type Quiz struct {
-	ID        string      `json:"id"`
-	Name      string      `json:"name"`
-	CreatedAt time.Time   `json:"createdAt"`
-	Questions []*Question `json:"questions"`
+	NodeID    string             `json:"id"`
+	ID        string             `json:"databaseID"`
+	Name      string             `json:"name"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Questions []*Question        `json:"questions"`
+	Version   int                `json:"version"`
+	Category  AssessmentCategory `json:"category"`
+	Weight    float64            `json:"weight"`
+}
+
+func (Quiz) IsNode() {}
+
+type RemapLOLinksResult struct {
+	CourseID   string            `json:"courseID"`
+	PloGroupID string            `json:"ploGroupID"`
+	Applied    bool              `json:"applied"`
+	Remapped   []*RemappedLOLink `json:"remapped"`
+	Unmapped   []*UnmappedLOLink `json:"unmapped"`
+}
+
+type RemappedLOLink struct {
+	LoID     string `json:"loID"`
+	OldPloid string `json:"oldPLOID"`
+	NewPloid string `json:"newPLOID"`
+}
+
+type RemoveCourseStaffResult struct {
+	CourseID  string `json:"courseID"`
+	TeacherID string `json:"teacherID"`
+}
+
+type Section struct {
+	ID       string  `json:"id"`
+	CourseID string  `json:"courseID"`
+	Name     string  `json:"name"`
+	Students []*User `json:"students"`
+	Staff    []*User `json:"staff"`
+}
+
+type StudentLetterGrade struct {
+	Student    *User   `json:"student"`
+	Percentage float64 `json:"percentage"`
+	Points     float64 `json:"points"`
+	Letter     string  `json:"letter"`
+}
+
+type Teacher struct {
+	NodeID   string     `json:"id"`
+	ID       string     `json:"databaseID"`
+	Email    string     `json:"email"`
+	Name     string     `json:"name"`
+	Surname  string     `json:"surname"`
+	Role     int        `json:"role"`
+	Active   bool       `json:"active"`
+	Programs []*Program `json:"programs"`
+	Courses  []*Course  `json:"courses"`
+}
+
+func (Teacher) IsNode() {}
+
+type Term struct {
+	ID        string     `json:"id"`
+	Code      string     `json:"code"`
+	Semester  int        `json:"semester"`
+	Year      int        `json:"year"`
+	StartDate time.Time  `json:"startDate"`
+	EndDate   time.Time  `json:"endDate"`
+	Status    TermStatus `json:"status"`
+}
+
+type TrashItem struct {
+	Type      TrashType  `json:"type"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deletedAt"`
+	PurgeAt   *time.Time `json:"purgeAt"`
+}
+
+type UnenrollResult struct {
+	CourseID  string `json:"courseID"`
+	StudentID string `json:"studentID"`
+}
+
+type UnmappedLOLink struct {
+	LoID  string `json:"loID"`
+	PloID string `json:"ploID"`
 }
 
 type User struct {
-	ID      string `json:"id"`
+	NodeID  string `json:"id"`
+	ID      string `json:"databaseID"`
 	Email   string `json:"email"`
 	Name    string `json:"name"`
 	Surname string `json:"surname"`
 }
 
+func (User) IsNode()         {}
+func (User) IsSearchResult() {}
+
 type AddPLOsResult struct {
 	ID string `json:"id"`
 }
@@ -328,3 +750,490 @@ type DeletePLOGroupResult struct {
 type DeletePLOResult struct {
 	ID string `json:"id"`
 }
+
+type AssessmentCategory string
+
+const (
+	AssessmentCategoryQuiz    AssessmentCategory = "QUIZ"
+	AssessmentCategoryMidterm AssessmentCategory = "MIDTERM"
+	AssessmentCategoryFinal   AssessmentCategory = "FINAL"
+	AssessmentCategoryProject AssessmentCategory = "PROJECT"
+)
+
+var AllAssessmentCategory = []AssessmentCategory{
+	AssessmentCategoryQuiz,
+	AssessmentCategoryMidterm,
+	AssessmentCategoryFinal,
+	AssessmentCategoryProject,
+}
+
+func (e AssessmentCategory) IsValid() bool {
+	switch e {
+	case AssessmentCategoryQuiz, AssessmentCategoryMidterm, AssessmentCategoryFinal, AssessmentCategoryProject:
+		return true
+	}
+	return false
+}
+
+func (e AssessmentCategory) String() string {
+	return string(e)
+}
+
+func (e *AssessmentCategory) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AssessmentCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AssessmentCategory", str)
+	}
+	return nil
+}
+
+func (e AssessmentCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CourseLockAction string
+
+const (
+	CourseLockActionFinalized CourseLockAction = "FINALIZED"
+	CourseLockActionUnlocked  CourseLockAction = "UNLOCKED"
+)
+
+var AllCourseLockAction = []CourseLockAction{
+	CourseLockActionFinalized,
+	CourseLockActionUnlocked,
+}
+
+func (e CourseLockAction) IsValid() bool {
+	switch e {
+	case CourseLockActionFinalized, CourseLockActionUnlocked:
+		return true
+	}
+	return false
+}
+
+func (e CourseLockAction) String() string {
+	return string(e)
+}
+
+func (e *CourseLockAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CourseLockAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CourseLockAction", str)
+	}
+	return nil
+}
+
+func (e CourseLockAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CourseStaffRole string
+
+const (
+	CourseStaffRoleOwner        CourseStaffRole = "OWNER"
+	CourseStaffRoleCoInstructor CourseStaffRole = "CO_INSTRUCTOR"
+	CourseStaffRoleTa           CourseStaffRole = "TA"
+)
+
+var AllCourseStaffRole = []CourseStaffRole{
+	CourseStaffRoleOwner,
+	CourseStaffRoleCoInstructor,
+	CourseStaffRoleTa,
+}
+
+func (e CourseStaffRole) IsValid() bool {
+	switch e {
+	case CourseStaffRoleOwner, CourseStaffRoleCoInstructor, CourseStaffRoleTa:
+		return true
+	}
+	return false
+}
+
+func (e CourseStaffRole) String() string {
+	return string(e)
+}
+
+func (e *CourseStaffRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CourseStaffRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CourseStaffRole", str)
+	}
+	return nil
+}
+
+func (e CourseStaffRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DeletionEntity string
+
+const (
+	DeletionEntityPlo      DeletionEntity = "PLO"
+	DeletionEntityPloGroup DeletionEntity = "PLO_GROUP"
+	DeletionEntityLo       DeletionEntity = "LO"
+	DeletionEntityLoLevel  DeletionEntity = "LO_LEVEL"
+)
+
+var AllDeletionEntity = []DeletionEntity{
+	DeletionEntityPlo,
+	DeletionEntityPloGroup,
+	DeletionEntityLo,
+	DeletionEntityLoLevel,
+}
+
+func (e DeletionEntity) IsValid() bool {
+	switch e {
+	case DeletionEntityPlo, DeletionEntityPloGroup, DeletionEntityLo, DeletionEntityLoLevel:
+		return true
+	}
+	return false
+}
+
+func (e DeletionEntity) String() string {
+	return string(e)
+}
+
+func (e *DeletionEntity) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeletionEntity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeletionEntity", str)
+	}
+	return nil
+}
+
+func (e DeletionEntity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EnrollmentStatus string
+
+const (
+	EnrollmentStatusEnrolled  EnrollmentStatus = "ENROLLED"
+	EnrollmentStatusWithdrawn EnrollmentStatus = "WITHDRAWN"
+	EnrollmentStatusAudit     EnrollmentStatus = "AUDIT"
+)
+
+var AllEnrollmentStatus = []EnrollmentStatus{
+	EnrollmentStatusEnrolled,
+	EnrollmentStatusWithdrawn,
+	EnrollmentStatusAudit,
+}
+
+func (e EnrollmentStatus) IsValid() bool {
+	switch e {
+	case EnrollmentStatusEnrolled, EnrollmentStatusWithdrawn, EnrollmentStatusAudit:
+		return true
+	}
+	return false
+}
+
+func (e EnrollmentStatus) String() string {
+	return string(e)
+}
+
+func (e *EnrollmentStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EnrollmentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EnrollmentStatus", str)
+	}
+	return nil
+}
+
+func (e EnrollmentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GradeRounding string
+
+const (
+	GradeRoundingNone   GradeRounding = "NONE"
+	GradeRoundingHalfUp GradeRounding = "HALF_UP"
+	GradeRoundingUp     GradeRounding = "UP"
+	GradeRoundingDown   GradeRounding = "DOWN"
+)
+
+var AllGradeRounding = []GradeRounding{
+	GradeRoundingNone,
+	GradeRoundingHalfUp,
+	GradeRoundingUp,
+	GradeRoundingDown,
+}
+
+func (e GradeRounding) IsValid() bool {
+	switch e {
+	case GradeRoundingNone, GradeRoundingHalfUp, GradeRoundingUp, GradeRoundingDown:
+		return true
+	}
+	return false
+}
+
+func (e GradeRounding) String() string {
+	return string(e)
+}
+
+func (e *GradeRounding) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GradeRounding(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GradeRounding", str)
+	}
+	return nil
+}
+
+func (e GradeRounding) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PLOContribution string
+
+const (
+	PLOContributionIntroduced PLOContribution = "INTRODUCED"
+	PLOContributionReinforced PLOContribution = "REINFORCED"
+	PLOContributionMastered   PLOContribution = "MASTERED"
+)
+
+var AllPLOContribution = []PLOContribution{
+	PLOContributionIntroduced,
+	PLOContributionReinforced,
+	PLOContributionMastered,
+}
+
+func (e PLOContribution) IsValid() bool {
+	switch e {
+	case PLOContributionIntroduced, PLOContributionReinforced, PLOContributionMastered:
+		return true
+	}
+	return false
+}
+
+func (e PLOContribution) String() string {
+	return string(e)
+}
+
+func (e *PLOContribution) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PLOContribution(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PLOContribution", str)
+	}
+	return nil
+}
+
+func (e PLOContribution) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type QuestionResultStatus string
+
+const (
+	QuestionResultStatusCreated   QuestionResultStatus = "CREATED"
+	QuestionResultStatusUpdated   QuestionResultStatus = "UPDATED"
+	QuestionResultStatusCleared   QuestionResultStatus = "CLEARED"
+	QuestionResultStatusUnchanged QuestionResultStatus = "UNCHANGED"
+	QuestionResultStatusInvalid   QuestionResultStatus = "INVALID"
+)
+
+var AllQuestionResultStatus = []QuestionResultStatus{
+	QuestionResultStatusCreated,
+	QuestionResultStatusUpdated,
+	QuestionResultStatusCleared,
+	QuestionResultStatusUnchanged,
+	QuestionResultStatusInvalid,
+}
+
+func (e QuestionResultStatus) IsValid() bool {
+	switch e {
+	case QuestionResultStatusCreated, QuestionResultStatusUpdated, QuestionResultStatusCleared, QuestionResultStatusUnchanged, QuestionResultStatusInvalid:
+		return true
+	}
+	return false
+}
+
+func (e QuestionResultStatus) String() string {
+	return string(e)
+}
+
+func (e *QuestionResultStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = QuestionResultStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid QuestionResultStatus", str)
+	}
+	return nil
+}
+
+func (e QuestionResultStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchType string
+
+const (
+	SearchTypeProgram SearchType = "PROGRAM"
+	SearchTypeCourse  SearchType = "COURSE"
+	SearchTypePlo     SearchType = "PLO"
+	SearchTypeLo      SearchType = "LO"
+	SearchTypeUser    SearchType = "USER"
+)
+
+var AllSearchType = []SearchType{
+	SearchTypeProgram,
+	SearchTypeCourse,
+	SearchTypePlo,
+	SearchTypeLo,
+	SearchTypeUser,
+}
+
+func (e SearchType) IsValid() bool {
+	switch e {
+	case SearchTypeProgram, SearchTypeCourse, SearchTypePlo, SearchTypeLo, SearchTypeUser:
+		return true
+	}
+	return false
+}
+
+func (e SearchType) String() string {
+	return string(e)
+}
+
+func (e *SearchType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchType", str)
+	}
+	return nil
+}
+
+func (e SearchType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TermStatus string
+
+const (
+	TermStatusPlanned TermStatus = "PLANNED"
+	TermStatusActive  TermStatus = "ACTIVE"
+	TermStatusClosed  TermStatus = "CLOSED"
+)
+
+var AllTermStatus = []TermStatus{
+	TermStatusPlanned,
+	TermStatusActive,
+	TermStatusClosed,
+}
+
+func (e TermStatus) IsValid() bool {
+	switch e {
+	case TermStatusPlanned, TermStatusActive, TermStatusClosed:
+		return true
+	}
+	return false
+}
+
+func (e TermStatus) String() string {
+	return string(e)
+}
+
+func (e *TermStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TermStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TermStatus", str)
+	}
+	return nil
+}
+
+func (e TermStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TrashType string
+
+const (
+	TrashTypeProgram  TrashType = "PROGRAM"
+	TrashTypePloGroup TrashType = "PLO_GROUP"
+	TrashTypeCourse   TrashType = "COURSE"
+	TrashTypeQuiz     TrashType = "QUIZ"
+)
+
+var AllTrashType = []TrashType{
+	TrashTypeProgram,
+	TrashTypePloGroup,
+	TrashTypeCourse,
+	TrashTypeQuiz,
+}
+
+func (e TrashType) IsValid() bool {
+	switch e {
+	case TrashTypeProgram, TrashTypePloGroup, TrashTypeCourse, TrashTypeQuiz:
+		return true
+	}
+	return false
+}
+
+func (e TrashType) String() string {
+	return string(e)
+}
+
+func (e *TrashType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TrashType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TrashType", str)
+	}
+	return nil
+}
+
+func (e TrashType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,317 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// A node ID is the base64 form of "<type>:<key>[:<key>...]", where the keys are
+// the parts of the record's primary key in schema order. Composite keys such as
+// LOlevel (loID, level) are encoded the same way, so clients never have to join
+// or split key parts themselves.
+
+func encodeNodeID(typename string, keys ...string) string {
+	raw := strings.Join(append([]string{typename}, keys...), ":")
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeNodeID(nodeID string) (string, []string, error) {
+	raw, err := base64.URLEncoding.DecodeString(nodeID)
+	if err != nil {
+		return "", nil, errors.New("malformed node id")
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) < 2 {
+		return "", nil, errors.New("malformed node id")
+	}
+	for _, part := range parts[1:] {
+		if part == "" {
+			return "", nil, errors.New("malformed node id")
+		}
+	}
+	return parts[0], parts[1:], nil
+}
+
+// requireNodeVisible rejects nodes the viewer may not read. Programs,
+// courses, outcomes and users follow the visible* filters and only teachers
+// can read teacher records. Quizzes and questions are limited to the course's
+// graders and the students enrolled in it, and results to the graders and the
+// student the result belongs to.
+func (r *Resolver) requireNodeVisible(ctx context.Context, viewer *viewer, typename string, keys []string) error {
+	var err error
+	switch typename {
+	case "Program":
+		_, err = r.Client.Program.FindFirst(
+			append(append(viewer.visiblePrograms(), livePrograms()...), db.Program.ID.Equals(keys[0]))...,
+		).Exec(ctx)
+	case "PLOGroup":
+		_, err = r.Client.PLOgroup.FindFirst(
+			append(append(viewer.visiblePLOGroups(), livePLOGroups()...), db.PLOgroup.ID.Equals(keys[0]))...,
+		).Exec(ctx)
+	case "PLO":
+		_, err = r.Client.PLO.FindFirst(
+			append(viewer.visiblePLOs(livePLOGroups()...), db.PLO.ID.Equals(keys[0]))...,
+		).Exec(ctx)
+	case "Course":
+		_, err = r.Client.Course.FindFirst(
+			append(append(viewer.visibleCourses(), liveCourses()...), db.Course.ID.Equals(keys[0]))...,
+		).Exec(ctx)
+	case "LO", "LOLevel":
+		_, err = r.Client.LO.FindFirst(
+			append(viewer.visibleLOs(liveCourses()...), db.LO.ID.Equals(keys[0]))...,
+		).Exec(ctx)
+	case "QuestionLink":
+		_, err = r.Client.LO.FindFirst(
+			append(viewer.visibleLOs(liveCourses()...), db.LO.ID.Equals(keys[1]))...,
+		).Exec(ctx)
+	case "User":
+		_, err = r.Client.User.FindFirst(
+			append(viewer.visibleUsers(), db.User.ID.Equals(keys[0]))...,
+		).Exec(ctx)
+	case "Teacher":
+		if !viewer.IsTeacher {
+			return errPermissionDenied
+		}
+	case "Quiz":
+		quiz, err := r.Client.Quiz.FindUnique(
+			db.Quiz.ID.Equals(keys[0]),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return r.requireQuizReader(ctx, viewer, quiz.CourseID)
+	case "Question", "QuestionResult":
+		question, err := r.Client.Question.FindUnique(
+			db.Question.ID.Equals(keys[0]),
+		).With(
+			db.Question.Quiz.Fetch(),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if typename == "QuestionResult" {
+			return r.requireGradeViewer(ctx, question.Quiz().CourseID, keys[1])
+		}
+		return r.requireQuizReader(ctx, viewer, question.Quiz().CourseID)
+	}
+	return err
+}
+
+func (r *queryResolver) loNode(ctx context.Context, id string) (*model.Lo, error) {
+	lo, err := r.Client.LO.FindFirst(
+		db.LO.ID.Equals(id),
+		db.LO.Course.Where(liveCourses()...),
+	).With(
+		db.LO.Levels.Fetch(),
+		db.LO.Links.Fetch().With(
+			db.LOlink.Plo.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Lo{}, err
+	}
+	levels := []*model.LOLevel{}
+	for _, level := range lo.Levels() {
+		levels = append(levels, &model.LOLevel{
+			LoID:        level.LoID,
+			Level:       level.Level,
+			Description: level.Description,
+		})
+	}
+	ploLinks := []*model.LOPLOLink{}
+	for _, link := range lo.Links() {
+		ploLinks = append(ploLinks, loLinkModel(link))
+	}
+	return &model.Lo{
+		ID:       lo.ID,
+		Title:    lo.Title,
+		Levels:   levels,
+		PloLinks: ploLinks,
+		Version:  lo.Version,
+	}, nil
+}
+
+func (r *queryResolver) loLevelNode(ctx context.Context, loID string, level string) (*model.LOLevel, error) {
+	lvl, err := strconv.Atoi(level)
+	if err != nil {
+		return &model.LOLevel{}, errors.New("malformed node id")
+	}
+	loLevel, err := r.Client.LOlevel.FindUnique(
+		db.LOlevel.LoIDLevel(
+			db.LOlevel.LoID.Equals(loID),
+			db.LOlevel.Level.Equals(lvl),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.LOLevel{}, err
+	}
+	return &model.LOLevel{
+		LoID:        loLevel.LoID,
+		Level:       loLevel.Level,
+		Description: loLevel.Description,
+	}, nil
+}
+
+func (r *queryResolver) userNode(ctx context.Context, id string) (*model.User, error) {
+	user, err := r.Client.User.FindUnique(
+		db.User.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.User{}, err
+	}
+	return &model.User{
+		ID:      user.ID,
+		Email:   user.Email,
+		Name:    user.Name,
+		Surname: user.Surname,
+	}, nil
+}
+
+func (r *queryResolver) ploGroupNode(ctx context.Context, id string) (*model.PLOGroup, error) {
+	ploGroup, err := r.Client.PLOgroup.FindFirst(
+		append(livePLOGroups(), db.PLOgroup.ID.Equals(id))...,
+	).Exec(ctx)
+	if err != nil {
+		return &model.PLOGroup{}, err
+	}
+	return ploGroupModel(*ploGroup), nil
+}
+
+func (r *queryResolver) ploNode(ctx context.Context, id string) (*model.Plo, error) {
+	plo, err := r.Client.PLO.FindFirst(
+		db.PLO.ID.Equals(id),
+		db.PLO.PloGroup.Where(livePLOGroups()...),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Plo{}, err
+	}
+	return &model.Plo{
+		ID:          plo.ID,
+		Title:       plo.Title,
+		Description: plo.Description,
+		PloGroupID:  plo.PloGroupID,
+		Version:     plo.Version,
+	}, nil
+}
+
+func (r *queryResolver) quizNode(ctx context.Context, id string, results ...db.QuestionResultWhereParam) (*model.Quiz, error) {
+	quiz, err := r.Client.Quiz.FindFirst(
+		append(liveQuizzes(), db.Quiz.ID.Equals(id))...,
+	).With(
+		db.Quiz.Questions.Fetch().With(
+			db.Question.Links.Fetch().With(
+				db.QuestionLink.LoLevel.Fetch(),
+			),
+			db.Question.Results.Fetch(results...),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Quiz{}, err
+	}
+	questions := []*model.Question{}
+	for _, question := range quiz.Questions() {
+		questions = append(questions, questionModel(question))
+	}
+	return &model.Quiz{
+		ID:        quiz.ID,
+		Name:      quiz.Name,
+		CreatedAt: quiz.CreatedAt,
+		Questions: questions,
+		Version:   quiz.Version,
+		Category:  model.AssessmentCategory(quiz.Category),
+		Weight:    quiz.Weight,
+	}, nil
+}
+
+func (r *queryResolver) questionNode(ctx context.Context, id string, results ...db.QuestionResultWhereParam) (*model.Question, error) {
+	question, err := r.Client.Question.FindUnique(
+		db.Question.ID.Equals(id),
+	).With(
+		db.Question.Links.Fetch().With(
+			db.QuestionLink.LoLevel.Fetch(),
+		),
+		db.Question.Results.Fetch(results...),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Question{}, err
+	}
+	return questionModel(*question), nil
+}
+
+func (r *queryResolver) questionResultNode(ctx context.Context, questionID string, studentID string) (*model.QuestionResult, error) {
+	result, err := r.Client.QuestionResult.FindUnique(
+		db.QuestionResult.QuestionIDStudentID(
+			db.QuestionResult.QuestionID.Equals(questionID),
+			db.QuestionResult.StudentID.Equals(studentID),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.QuestionResult{}, err
+	}
+	return &model.QuestionResult{
+		QuestionID: result.QuestionID,
+		StudentID:  result.StudentID,
+		Score:      result.Score,
+	}, nil
+}
+
+func (r *queryResolver) questionLinkNode(ctx context.Context, questionID string, loID string, level string) (*model.QuestionLink, error) {
+	lvl, err := strconv.Atoi(level)
+	if err != nil {
+		return &model.QuestionLink{}, errors.New("malformed node id")
+	}
+	link, err := r.Client.QuestionLink.FindUnique(
+		db.QuestionLink.QuestionIDLoIDLevel(
+			db.QuestionLink.QuestionID.Equals(questionID),
+			db.QuestionLink.LoID.Equals(loID),
+			db.QuestionLink.Level.Equals(lvl),
+		),
+	).With(
+		db.QuestionLink.LoLevel.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return &model.QuestionLink{}, err
+	}
+	return &model.QuestionLink{
+		QuestionID:  link.QuestionID,
+		LoID:        link.LoID,
+		Level:       link.Level,
+		Description: link.LoLevel().Description,
+		Weight:      link.Weight,
+	}, nil
+}
+
+// questionModel expects the question to be fetched with its results and its
+// links, including each link's LO level.
+func questionModel(question db.QuestionModel) *model.Question {
+	results := []*model.QuestionResult{}
+	loLinks := []*model.QuestionLink{}
+	for _, result := range question.Results() {
+		results = append(results, &model.QuestionResult{
+			QuestionID: result.QuestionID,
+			StudentID:  result.StudentID,
+			Score:      result.Score,
+		})
+	}
+	for _, loLink := range question.Links() {
+		loLinks = append(loLinks, &model.QuestionLink{
+			QuestionID:  loLink.QuestionID,
+			LoID:        loLink.LoID,
+			Level:       loLink.Level,
+			Description: loLink.LoLevel().Description,
+			Weight:      loLink.Weight,
+		})
+	}
+	return &model.Question{
+		ID:       question.ID,
+		Title:    question.Title,
+		MaxScore: question.MaxScore,
+		Results:  results,
+		LoLinks:  loLinks,
+	}
+}
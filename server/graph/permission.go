@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"api/server/db"
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Role levels stored in Teacher.role. Students have no teacher record at all.
+const (
+	roleTeacher      = 1
+	roleProgramChair = 2
+	roleDeveloper    = 3
+)
+
+var errPermissionDenied = errors.New("permission denied")
+
+type viewer struct {
+	ID        string
+	IsTeacher bool
+	Role      int
+}
+
+func (r *Resolver) getViewer(ctx context.Context) (*viewer, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, errors.New("user not found")
+	}
+	teacher, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(userID),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return &viewer{ID: userID}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if !teacher.Active {
+		return nil, errors.New("account is deactivated")
+	}
+	return &viewer{
+		ID:        userID,
+		IsTeacher: true,
+		Role:      teacher.Role,
+	}, nil
+}
+
+// RequireActiveAccount is a field middleware that rejects every mutation from
+// a deactivated teacher, including those that don't check the viewer
+// themselves.
+func (r *Resolver) RequireActiveAccount(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" {
+		return next(ctx)
+	}
+	if _, err := r.getViewer(ctx); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
+
+// requireRole returns the viewer if they are an active teacher with at least
+// the given role.
+func (r *Resolver) requireRole(ctx context.Context, role int) (*viewer, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.IsTeacher || viewer.Role < role {
+		return nil, errPermissionDenied
+	}
+	return viewer, nil
+}
+
+// The visible* functions return the filters that limit a query to the records
+// the viewer may read. Teachers can read every program, course and outcome;
+// a student only reaches what is connected to the courses they're enrolled in.
+
+func (v *viewer) visiblePrograms() []db.ProgramWhereParam {
+	if v.IsTeacher {
+		return nil
+	}
+	return []db.ProgramWhereParam{
+		db.Program.Courses.Some(v.takenCourse()...),
+	}
+}
+
+func (v *viewer) visibleCourses() []db.CourseWhereParam {
+	if v.IsTeacher {
+		return nil
+	}
+	return v.takenCourse()
+}
+
+func (v *viewer) visiblePLOGroups() []db.PLOgroupWhereParam {
+	if v.IsTeacher {
+		return nil
+	}
+	return []db.PLOgroupWhereParam{
+		db.PLOgroup.Courses.Some(v.takenCourse()...),
+	}
+}
+
+// visiblePLOs and visibleLOs also take filters on the PLO group or course,
+// since a second Where on the same relation would replace the viewer's.
+func (v *viewer) visiblePLOs(ploGroup ...db.PLOgroupWhereParam) []db.PLOWhereParam {
+	if !v.IsTeacher {
+		ploGroup = append(ploGroup, db.PLOgroup.Courses.Some(v.takenCourse()...))
+	}
+	if len(ploGroup) == 0 {
+		return nil
+	}
+	return []db.PLOWhereParam{
+		db.PLO.PloGroup.Where(ploGroup...),
+	}
+}
+
+func (v *viewer) visibleLOs(course ...db.CourseWhereParam) []db.LOWhereParam {
+	if !v.IsTeacher {
+		course = append(course, v.takenCourse()...)
+	}
+	if len(course) == 0 {
+		return nil
+	}
+	return []db.LOWhereParam{
+		db.LO.Course.Where(course...),
+	}
+}
+
+// visibleUsers lets program chairs and developers read every user, teachers
+// read the students of the courses they're on the staff of, and students read themselves.
+func (v *viewer) visibleUsers() []db.UserWhereParam {
+	if v.IsTeacher && v.Role >= roleProgramChair {
+		return nil
+	}
+	if !v.IsTeacher {
+		return []db.UserWhereParam{db.User.ID.Equals(v.ID)}
+	}
+	return []db.UserWhereParam{
+		db.User.Or(
+			db.User.ID.Equals(v.ID),
+			db.User.Student.Where(
+				db.Student.Enrollments.Some(
+					db.Enrollment.Course.Where(
+						db.Course.Or(
+							db.Course.TeacherID.Equals(v.ID),
+							db.Course.Staff.Some(
+								db.CourseStaff.TeacherID.Equals(v.ID),
+							),
+						),
+					),
+				),
+			),
+		),
+	}
+}
+
+// visibleResults limits results to the viewer's own when the viewer is a
+// student. Teachers must be checked against the course's staff separately.
+func (v *viewer) visibleResults() []db.QuestionResultWhereParam {
+	if v.IsTeacher {
+		return nil
+	}
+	return []db.QuestionResultWhereParam{
+		db.QuestionResult.StudentID.Equals(v.ID),
+	}
+}
+
+func (v *viewer) takenCourse() []db.CourseWhereParam {
+	return []db.CourseWhereParam{
+		db.Course.Enrollments.Some(
+			db.Enrollment.StudentID.Equals(v.ID),
+		),
+	}
+}
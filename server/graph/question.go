@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"api/server/graph/model"
+	"fmt"
+)
+
+func questionStudentIDs(inputs ...*model.CreateQuestionInput) []string {
+	studentIDs := []string{}
+	for _, questionInput := range inputs {
+		for _, resultInput := range questionInput.Results {
+			studentIDs = append(studentIDs, resultInput.StudentID)
+		}
+	}
+	return studentIDs
+}
+
+// validateQuestionInput records every row of a question and its results that
+// can't be written, using path as the prefix of the reported rows.
+func validateQuestionInput(path string, input *model.CreateQuestionInput, students map[string]bool, errs *inputErrors) {
+	maxScoreMessage := validateMaxScore(input.MaxScore)
+	if maxScoreMessage != "" {
+		errs.add(path, "%s", maxScoreMessage)
+	}
+	seen := map[string]bool{}
+	for i, resultInput := range input.Results {
+		resultPath := fmt.Sprintf("%s.results[%d]", path, i)
+		if !students[resultInput.StudentID] {
+			errs.add(resultPath, "student %s isn't enrolled in the course", resultInput.StudentID)
+		} else if seen[resultInput.StudentID] {
+			errs.add(resultPath, "student %s has more than one result", resultInput.StudentID)
+		}
+		seen[resultInput.StudentID] = true
+		if maxScoreMessage != "" {
+			continue
+		}
+		if message := validateScore(resultInput.Score, input.MaxScore); message != "" {
+			errs.add(resultPath, "%s", message)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+// Moving a course to another PLO group used to drop all of its LO links.
+// editCourse now refuses to move a course with links, and remapLOLinks
+// carries them over instead: each link's PLO is replaced by the PLOs it maps
+// to in the new group, taken from the explicit rules first and, when asked,
+// from the mappings between revisions of the curriculum. Links that map to
+// nothing are reported so the teacher can redo them by hand.
+
+// checkNoLOLinks rejects moving a course to another PLO group by editing it
+// while its LOs are linked to PLOs, since the links would be lost.
+func (r *Resolver) checkNoLOLinks(ctx context.Context, courseID string) error {
+	_, err := r.Client.LOlink.FindFirst(
+		db.LOlink.Lo.Where(
+			db.LO.CourseID.Equals(courseID),
+		),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return errors.New("the course's LOs are linked to PLOs, use remapLOLinks to move it to another PLO group")
+}
+
+// remapLOLinks returns the transactions that rewrite a course's LO links for
+// a PLO group, together with the links it kept and dropped. The course must
+// be loaded with its LOs and their links. The writes only take effect once
+// the course has been moved to the group at the version after the one it was
+// loaded at, see RemapLOLinks, so links changed since it was loaded are never
+// overwritten.
+func (r *Resolver) remapLOLinks(ctx context.Context, course db.CourseModel, ploGroupID string, rules []*model.PLORemapRule, useRevisionMapping bool) ([]transaction.Param, *model.RemapLOLinksResult, error) {
+	ploGroup, err := r.Client.PLOgroup.FindFirst(
+		append(livePLOGroups(), db.PLOgroup.ID.Equals(ploGroupID))...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ploGroup.ProgramID != course.ProgramID {
+		return nil, nil, errors.New("PLO group belongs to another program")
+	}
+	plos, err := r.Client.PLO.FindMany(
+		db.PLO.PloGroupID.Equals(ploGroupID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	inGroup := map[string]bool{}
+	for _, plo := range plos {
+		inGroup[plo.ID] = true
+	}
+
+	errs := inputErrors{}
+	explicit := map[string][]string{}
+	for i, rule := range rules {
+		if !inGroup[rule.NewPloid] {
+			errs.add(fmt.Sprintf("rules[%d].newPLOID", i), "PLO %s isn't in the PLO group", rule.NewPloid)
+			continue
+		}
+		explicit[rule.OldPloid] = append(explicit[rule.OldPloid], rule.NewPloid)
+	}
+	if err := errs.err(); err != nil {
+		return nil, nil, err
+	}
+	var translator *ploTranslator
+	if useRevisionMapping {
+		translator, err = r.newPLOTranslator(ctx, ploGroupID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	result := &model.RemapLOLinksResult{
+		CourseID:   course.ID,
+		PloGroupID: ploGroupID,
+		Remapped:   []*model.RemappedLOLink{},
+		Unmapped:   []*model.UnmappedLOLink{},
+	}
+	next := course.Version + 1
+	transactions := []transaction.Param{
+		r.Client.LOlink.FindMany(
+			db.LOlink.Lo.Where(
+				db.LO.CourseID.Equals(course.ID),
+				db.LO.Course.Where(
+					db.Course.Version.Equals(next),
+				),
+			),
+		).Delete().Tx(),
+	}
+	guard := []transaction.Param{}
+	for _, lo := range course.Los() {
+		linked := map[string]bool{}
+		for _, link := range lo.Links() {
+			newPLOIDs, ok := explicit[link.PloID]
+			if !ok && inGroup[link.PloID] {
+				newPLOIDs = []string{link.PloID}
+			} else if !ok && translator != nil {
+				newPLOIDs = translator.translate(link.PloID)
+			}
+			if len(newPLOIDs) == 0 {
+				result.Unmapped = append(result.Unmapped, &model.UnmappedLOLink{
+					LoID:  lo.ID,
+					PloID: link.PloID,
+				})
+				continue
+			}
+			for _, newPLOID := range newPLOIDs {
+				result.Remapped = append(result.Remapped, &model.RemappedLOLink{
+					LoID:     lo.ID,
+					OldPloid: link.PloID,
+					NewPloid: newPLOID,
+				})
+				if linked[newPLOID] {
+					continue
+				}
+				linked[newPLOID] = true
+				transactions = append(transactions, r.Client.LOlink.CreateOne(
+					db.LOlink.Lo.Link(
+						db.LO.ID.Equals(lo.ID),
+					),
+					db.LOlink.Plo.Link(
+						db.PLO.ID.Equals(newPLOID),
+					),
+					linkParams(link)...,
+				).Tx())
+				if len(guard) == 0 {
+					guard = r.remapGuard(lo.ID, newPLOID, link, next)
+				}
+			}
+		}
+	}
+	return append(transactions, guard...), result, nil
+}
+
+// remapGuard returns the writes that roll a remap back if the course wasn't
+// moved. The deletes of the old links are skipped then, but the creates
+// aren't, so one created link is deleted again and updating it fails.
+func (r *Resolver) remapGuard(loID string, ploID string, link db.LOlinkModel, next int) []transaction.Param {
+	return []transaction.Param{
+		r.Client.LOlink.FindMany(
+			db.LOlink.LoID.Equals(loID),
+			db.LOlink.PloID.Equals(ploID),
+			db.LOlink.Lo.Where(
+				db.LO.Course.Where(
+					db.Course.Version.Not(next),
+				),
+			),
+		).Delete().Tx(),
+		r.Client.LOlink.FindUnique(
+			db.LOlink.LoIDPloID(
+				db.LOlink.LoID.Equals(loID),
+				db.LOlink.PloID.Equals(ploID),
+			),
+		).Update(
+			linkParams(link)...,
+		).Tx(),
+	}
+}
@@ -1,6 +1,9 @@
 package graph
 
-import "api/server/db"
+import (
+	"api/server/db"
+	"time"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -8,4 +11,13 @@ import "api/server/db"
 
 type Resolver struct {
 	Client *db.PrismaClient
+	// IdempotencyWindow is how long idempotency keys of create mutations are
+	// remembered. DefaultIdempotencyWindow is used when it's zero.
+	IdempotencyWindow time.Duration
+	// TrashRetention is how long trashed items are kept before PurgeTrash
+	// deletes them. DefaultTrashRetention is used when it's zero.
+	TrashRetention time.Duration
+	// AuditRetention is how long audit log entries are kept.
+	// DefaultAuditRetention is used when it's zero.
+	AuditRetention time.Duration
 }
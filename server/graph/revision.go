@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+)
+
+// A PLO group is one revision of a program's curriculum. Revising it copies
+// its PLOs into a new group and freezes the old one, so courses linked to the
+// old revision keep the PLOs they were assessed against. PLO mappings record
+// which PLOs of the next revision each old PLO became, which lets dashboards
+// report results from any revision against a chosen one.
+
+// ploRevisions returns every revision of the PLO group's curriculum, oldest
+// first.
+func (r *Resolver) ploRevisions(ctx context.Context, ploGroupID string) ([]db.PLOgroupModel, error) {
+	ploGroup, err := r.Client.PLOgroup.FindUnique(
+		db.PLOgroup.ID.Equals(ploGroupID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	programGroups, err := r.Client.PLOgroup.FindMany(
+		db.PLOgroup.ProgramID.Equals(ploGroup.ProgramID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]db.PLOgroupModel{}
+	next := map[string]db.PLOgroupModel{}
+	for _, group := range programGroups {
+		byID[group.ID] = group
+		if previousID, ok := group.PreviousID(); ok {
+			next[previousID] = group
+		}
+	}
+	first := *ploGroup
+	for {
+		previousID, ok := first.PreviousID()
+		if !ok {
+			break
+		}
+		first = byID[previousID]
+	}
+	revisions := []db.PLOgroupModel{first}
+	for {
+		group, ok := next[revisions[len(revisions)-1].ID]
+		if !ok {
+			break
+		}
+		revisions = append(revisions, group)
+	}
+	return revisions, nil
+}
+
+func (r *Resolver) requireCurrentPLOGroup(ctx context.Context, ploGroupID string) error {
+	ploGroup, err := r.Client.PLOgroup.FindFirst(
+		append(livePLOGroups(), db.PLOgroup.ID.Equals(ploGroupID))...,
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if _, superseded := ploGroup.SupersededAt(); superseded {
+		return errors.New("this PLO group has been revised, change its latest revision instead")
+	}
+	return nil
+}
+
+// checkPLOGroupTrashable rejects trashing a superseded revision or a PLO
+// group that courses still use. Purging either would take the LO links of
+// those courses with it, and a superseded one would also break the chain of
+// revisions.
+func (r *Resolver) checkPLOGroupTrashable(ctx context.Context, ploGroupID string) error {
+	if err := r.requireCurrentPLOGroup(ctx, ploGroupID); err != nil {
+		return err
+	}
+	_, err := r.Client.Course.FindFirst(
+		append(liveCourses(), db.Course.PloGroupID.Equals(ploGroupID))...,
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return errors.New("this PLO group is used by courses, move them to another PLO group first")
+}
+
+func (r *Resolver) requireCurrentPLO(ctx context.Context, ploID string) error {
+	plo, err := r.Client.PLO.FindUnique(
+		db.PLO.ID.Equals(ploID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return r.requireCurrentPLOGroup(ctx, plo.PloGroupID)
+}
+
+// ploTranslator maps PLOs of any revision in a curriculum onto the PLOs of
+// one target revision by following the mappings between consecutive
+// revisions, forwards or backwards.
+type ploTranslator struct {
+	target    db.PLOgroupModel
+	groups    []string
+	plos      map[string]db.PLOModel
+	revisions map[string]int
+	forward   map[string][]string
+	backward  map[string][]string
+}
+
+func (r *Resolver) newPLOTranslator(ctx context.Context, ploGroupID string) (*ploTranslator, error) {
+	revisions, err := r.ploRevisions(ctx, ploGroupID)
+	if err != nil {
+		return nil, err
+	}
+	t := &ploTranslator{
+		groups:    []string{},
+		plos:      map[string]db.PLOModel{},
+		revisions: map[string]int{},
+		forward:   map[string][]string{},
+		backward:  map[string][]string{},
+	}
+	revisionOf := map[string]int{}
+	for _, group := range revisions {
+		t.groups = append(t.groups, group.ID)
+		revisionOf[group.ID] = group.Revision
+		if group.ID == ploGroupID {
+			t.target = group
+		}
+	}
+	plos, err := r.Client.PLO.FindMany(
+		db.PLO.PloGroupID.In(t.groups),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, plo := range plos {
+		t.plos[plo.ID] = plo
+		t.revisions[plo.ID] = revisionOf[plo.PloGroupID]
+	}
+	mappings, err := r.Client.PLOmapping.FindMany(
+		db.PLOmapping.OldPLO.Where(
+			db.PLO.PloGroupID.In(t.groups),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, mapping := range mappings {
+		t.forward[mapping.OldPLOID] = append(t.forward[mapping.OldPLOID], mapping.NewPLOID)
+		t.backward[mapping.NewPLOID] = append(t.backward[mapping.NewPLOID], mapping.OldPLOID)
+	}
+	return t, nil
+}
+
+// covers reports whether a PLO belongs to one of the curriculum's revisions.
+func (t *ploTranslator) covers(ploID string) bool {
+	_, ok := t.revisions[ploID]
+	return ok
+}
+
+// translate returns the target revision's PLOs that a PLO corresponds to. It
+// returns nothing for PLOs outside the curriculum or dropped along the way.
+func (t *ploTranslator) translate(ploID string) []string {
+	revision, ok := t.revisions[ploID]
+	if !ok {
+		return nil
+	}
+	current := []string{ploID}
+	for ; revision != t.target.Revision && len(current) > 0; revision += sign(t.target.Revision - revision) {
+		edges := t.forward
+		if revision > t.target.Revision {
+			edges = t.backward
+		}
+		seen := map[string]bool{}
+		next := []string{}
+		for _, id := range current {
+			for _, mapped := range edges[id] {
+				if !seen[mapped] {
+					seen[mapped] = true
+					next = append(next, mapped)
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+
+func ploGroupModel(ploGroup db.PLOgroupModel) *model.PLOGroup {
+	group := &model.PLOGroup{
+		ID:       ploGroup.ID,
+		Name:     ploGroup.Name,
+		Revision: ploGroup.Revision,
+	}
+	if previousID, ok := ploGroup.PreviousID(); ok {
+		group.PreviousID = &previousID
+	}
+	if supersededAt, ok := ploGroup.SupersededAt(); ok {
+		group.SupersededAt = &supersededAt
+	}
+	return group
+}
@@ -0,0 +1,58 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+)
+
+func (r *queryResolver) AuditLog(ctx context.Context, filter *model.AuditLogFilter) ([]*model.AuditLogEntry, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return []*model.AuditLogEntry{}, err
+	}
+	if filter == nil {
+		filter = &model.AuditLogFilter{}
+	}
+	limit := 100
+	if filter.Limit != nil && *filter.Limit > 0 && *filter.Limit < 1000 {
+		limit = *filter.Limit
+	}
+	allEntries, err := r.Client.AuditLog.FindMany(
+		db.AuditLog.ActorID.EqualsIfPresent(filter.ActorID),
+		db.AuditLog.Operation.EqualsIfPresent(filter.Operation),
+		db.AuditLog.Entity.EqualsIfPresent(filter.Entity),
+		db.AuditLog.EntityID.EqualsIfPresent(filter.EntityID),
+		db.AuditLog.CreatedAt.GteIfPresent(filter.Since),
+		db.AuditLog.CreatedAt.LtIfPresent(filter.Until),
+	).OrderBy(
+		db.AuditLog.CreatedAt.Order(db.SortOrderDesc),
+	).Take(limit).Exec(ctx)
+	if err != nil {
+		return []*model.AuditLogEntry{}, err
+	}
+	entries := []*model.AuditLogEntry{}
+	for _, entry := range allEntries {
+		entity, _ := entry.Entity()
+		entityID, _ := entry.EntityID()
+		before, _ := entry.Before()
+		after, _ := entry.After()
+		mutationErr, _ := entry.Error()
+		entries = append(entries, &model.AuditLogEntry{
+			ID:        entry.ID,
+			CreatedAt: entry.CreatedAt,
+			ActorID:   entry.ActorID,
+			Role:      entry.Role,
+			Operation: entry.Operation,
+			Entity:    optionalString(entity),
+			EntityID:  optionalString(entityID),
+			Arguments: entry.Arguments,
+			Before:    optionalString(before),
+			After:     optionalString(after),
+			Error:     optionalString(mutationErr),
+		})
+	}
+	return entries, nil
+}
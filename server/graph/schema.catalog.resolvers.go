@@ -0,0 +1,176 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/generated"
+	"api/server/graph/model"
+	"context"
+)
+
+func (r *catalogCourseResolver) Offerings(ctx context.Context, obj *model.CatalogCourse) ([]*model.Course, error) {
+	allCourses, err := r.Client.Course.FindMany(
+		append(liveCourses(), db.Course.CatalogCourseID.Equals(obj.ID))...,
+	).OrderBy(
+		db.Course.Year.Order(db.SortOrderAsc),
+		db.Course.Semester.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.Course{}, err
+	}
+	courses := []*model.Course{}
+	for _, course := range allCourses {
+		ploGroupID, _ := course.PloGroupID()
+		teacherID, _ := course.TeacherID()
+		courses = append(courses, &model.Course{
+			ID:          course.ID,
+			Name:        course.Name,
+			Description: course.Description,
+			Semester:    course.Semester,
+			Year:        course.Year,
+			PloGroupID:  ploGroupID,
+			ProgramID:   course.ProgramID,
+			TeacherID:   teacherID,
+			Version:     course.Version,
+		})
+	}
+	return courses, nil
+}
+
+func (r *catalogCourseResolver) Attainment(ctx context.Context, obj *model.CatalogCourse) ([]*model.CatalogAttainment, error) {
+	allCourses, err := r.Client.Course.FindMany(
+		append(liveCourses(), db.Course.CatalogCourseID.Equals(obj.ID))...,
+	).OrderBy(
+		db.Course.Year.Order(db.SortOrderAsc),
+		db.Course.Semester.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.CatalogAttainment{}, err
+	}
+	attainment := []*model.CatalogAttainment{}
+	for _, course := range allCourses {
+		offering, err := r.offeringAttainment(ctx, course)
+		if err != nil {
+			return []*model.CatalogAttainment{}, err
+		}
+		attainment = append(attainment, offering)
+	}
+	return attainment, nil
+}
+
+func (r *courseResolver) CatalogCourse(ctx context.Context, obj *model.Course) (*model.CatalogCourse, error) {
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(obj.ID),
+	).With(
+		db.Course.CatalogCourse.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalogCourse, ok := course.CatalogCourse()
+	if !ok {
+		return nil, nil
+	}
+	return catalogCourseModel(*catalogCourse), nil
+}
+
+func (r *mutationResolver) CreateCatalogCourse(ctx context.Context, programID string, input model.CreateCatalogCourseInput) (*model.CatalogCourse, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	if err := validateCatalogCourseInput(input); err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	catalogCourse, err := r.Client.CatalogCourse.CreateOne(
+		db.CatalogCourse.Code.Set(input.Code),
+		db.CatalogCourse.Name.Set(input.Name),
+		db.CatalogCourse.Credits.Set(input.Credits),
+		db.CatalogCourse.Program.Link(
+			db.Program.ID.Equals(programID),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	return catalogCourseModel(*catalogCourse), nil
+}
+
+func (r *mutationResolver) EditCatalogCourse(ctx context.Context, id string, input model.CreateCatalogCourseInput) (*model.CatalogCourse, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	if err := validateCatalogCourseInput(input); err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	catalogCourse, err := r.Client.CatalogCourse.FindUnique(
+		db.CatalogCourse.ID.Equals(id),
+	).Update(
+		db.CatalogCourse.Code.Set(input.Code),
+		db.CatalogCourse.Name.Set(input.Name),
+		db.CatalogCourse.Credits.Set(input.Credits),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	return catalogCourseModel(*catalogCourse), nil
+}
+
+func (r *mutationResolver) SetCourseCatalog(ctx context.Context, courseID string, catalogCourseID *string) (*model.Course, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseOwners); err != nil {
+		return &model.Course{}, err
+	}
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Course{}, err
+	}
+	params := []db.CourseSetParam{
+		db.Course.CatalogCourse.Unlink(),
+	}
+	if catalogCourseID != nil {
+		params, err = r.courseCatalogParams(ctx, model.CreateCourseInput{CatalogCourseID: catalogCourseID}, course.ProgramID)
+		if err != nil {
+			return &model.Course{}, err
+		}
+	}
+	if _, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).Update(params...).Exec(ctx); err != nil {
+		return &model.Course{}, err
+	}
+	return (&queryResolver{r.Resolver}).Course(ctx, courseID)
+}
+
+func (r *queryResolver) CatalogCourses(ctx context.Context, programID string) ([]*model.CatalogCourse, error) {
+	allCatalogCourses, err := r.Client.CatalogCourse.FindMany(
+		db.CatalogCourse.ProgramID.Equals(programID),
+	).OrderBy(
+		db.CatalogCourse.Code.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.CatalogCourse{}, err
+	}
+	catalogCourses := []*model.CatalogCourse{}
+	for _, catalogCourse := range allCatalogCourses {
+		catalogCourses = append(catalogCourses, catalogCourseModel(catalogCourse))
+	}
+	return catalogCourses, nil
+}
+
+func (r *queryResolver) CatalogCourse(ctx context.Context, id string) (*model.CatalogCourse, error) {
+	catalogCourse, err := r.Client.CatalogCourse.FindUnique(
+		db.CatalogCourse.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CatalogCourse{}, err
+	}
+	return catalogCourseModel(*catalogCourse), nil
+}
+
+// CatalogCourse returns generated.CatalogCourseResolver implementation.
+func (r *Resolver) CatalogCourse() generated.CatalogCourseResolver { return &catalogCourseResolver{r} }
+
+type catalogCourseResolver struct{ *Resolver }
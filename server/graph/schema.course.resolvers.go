@@ -9,138 +9,391 @@ import (
 	"api/server/graph/model"
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prisma/prisma-client-go/runtime/transaction"
 )
 
-func (r *mutationResolver) CreateCourse(ctx context.Context, programID string, input model.CreateCourseInput) (*model.Course, error) {
-	teacherID, ok := ctx.Value("user_id").(string)
-	if !ok || teacherID == "" {
-		return &model.Course{}, errors.New("user not found")
-	}
-	createdCourse, err := r.Client.Course.CreateOne(
-		db.Course.Name.Set(input.Name),
-		db.Course.Description.Set(input.Description),
-		db.Course.Semester.Set(input.Semester),
-		db.Course.Year.Set(input.Year),
-		db.Course.Program.Link(
-			db.Program.ID.Equals(programID),
-		),
-		db.Course.PloGroup.Link(
-			db.PLOgroup.ID.Equals(input.PloGroupID),
-		),
-		db.Course.Teacher.Link(
-			db.Teacher.ID.Equals(teacherID),
-		),
+func (r *courseResolver) ID(ctx context.Context, obj *model.Course) (string, error) {
+	return encodeNodeID("Course", obj.ID), nil
+}
+
+func (r *courseResolver) Staff(ctx context.Context, obj *model.Course) ([]*model.CourseStaff, error) {
+	return r.courseStaff(ctx, obj.ID)
+}
+
+func (r *courseResolver) Term(ctx context.Context, obj *model.Course) (*model.Term, error) {
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(obj.ID),
+	).With(
+		db.Course.Term.Fetch(),
 	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	term, ok := course.Term()
+	if !ok {
+		return nil, nil
+	}
+	return termModel(*term), nil
+}
+
+func (r *lOResolver) ID(ctx context.Context, obj *model.Lo) (string, error) {
+	return encodeNodeID("LO", obj.ID), nil
+}
+
+func (r *lOLevelResolver) ID(ctx context.Context, obj *model.LOLevel) (string, error) {
+	return encodeNodeID("LOLevel", obj.LoID, strconv.Itoa(obj.Level)), nil
+}
+
+func (r *mutationResolver) CreateCourse(ctx context.Context, programID string, input model.CreateCourseInput, idempotencyKey *string) (*model.Course, error) {
+	created := &model.Course{}
+	err := r.idempotent(ctx, idempotencyKey, "createCourse", []interface{}{programID, input}, created, func() error {
+		teacherID, ok := ctx.Value("user_id").(string)
+		if !ok || teacherID == "" {
+			return errors.New("user not found")
+		}
+		termParams, err := r.courseTermParams(ctx, &input)
+		if err != nil {
+			return err
+		}
+		catalogParams, err := r.courseCatalogParams(ctx, input, programID)
+		if err != nil {
+			return err
+		}
+		createdCourse, err := r.Client.Course.CreateOne(
+			db.Course.Name.Set(input.Name),
+			db.Course.Description.Set(input.Description),
+			db.Course.Semester.Set(input.Semester),
+			db.Course.Year.Set(input.Year),
+			db.Course.Program.Link(
+				db.Program.ID.Equals(programID),
+			),
+			append(append(termParams, catalogParams...),
+				db.Course.PloGroup.Link(
+					db.PLOgroup.ID.Equals(input.PloGroupID),
+				),
+				db.Course.Teacher.Link(
+					db.Teacher.ID.Equals(teacherID),
+				),
+			)...,
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		ploGroupID, _ := createdCourse.PloGroupID()
+		*created = model.Course{
+			ID:          createdCourse.ID,
+			Name:        createdCourse.Name,
+			Description: createdCourse.Description,
+			Semester:    createdCourse.Semester,
+			Year:        createdCourse.Year,
+			PloGroupID:  ploGroupID,
+			ProgramID:   createdCourse.ProgramID,
+			TeacherID:   teacherID,
+			Version:     createdCourse.Version,
+		}
+		return nil
+	})
 	if err != nil {
 		return &model.Course{}, err
 	}
-	ploGroupID, _ := createdCourse.PloGroupID()
-	return &model.Course{
-		ID:          createdCourse.ID,
-		Name:        createdCourse.Name,
-		Description: createdCourse.Description,
-		Semester:    createdCourse.Semester,
-		Year:        createdCourse.Year,
-		PloGroupID:  ploGroupID,
-		ProgramID:   createdCourse.ProgramID,
-		TeacherID:   teacherID,
-	}, nil
+	return created, nil
 }
 
-func (r *mutationResolver) EditCourse(ctx context.Context, id string, input model.CreateCourseInput) (*model.Course, error) {
+func (r *mutationResolver) EditCourse(ctx context.Context, id string, input model.CreateCourseInput, expectedVersion *int) (*model.Course, error) {
+	if err := r.requireUnlockedCourse(ctx, id, courseEditors); err != nil {
+		return &model.Course{}, err
+	}
+	if err := r.courseTerm(ctx, &input); err != nil {
+		return &model.Course{}, err
+	}
 	course, err := r.Client.Course.FindUnique(db.Course.ID.Equals(id)).Exec(ctx)
 	if err != nil {
 		return &model.Course{}, err
 	}
-	ploGroupID, _ := course.PloGroupID()
-	if ploGroupID != input.PloGroupID {
-		_, err := r.Client.LOlink.FindMany(
-			db.LOlink.Lo.Where(
-				db.LO.Course.Where(
-					db.Course.ID.Equals(id),
+	if err := r.courseCatalogCourse(ctx, input, course.ProgramID); err != nil {
+		return &model.Course{}, err
+	}
+	where := append(liveCourses(), db.Course.ID.Equals(id), db.Course.Version.EqualsIfPresent(expectedVersion))
+	if ploGroupID, _ := course.PloGroupID(); ploGroupID != input.PloGroupID {
+		if err := r.checkNoLOLinks(ctx, id); err != nil {
+			return &model.Course{}, err
+		}
+		// a link created since the check bumps the version, see
+		// bumpCourseVersion
+		where = append(where, db.Course.Version.Equals(course.Version))
+	}
+	err = updateVersioned(func() (*db.BatchResult, error) {
+		return r.Client.Course.FindMany(
+			where...,
+		).Update(
+			db.Course.Name.Set(input.Name),
+			db.Course.Description.Set(input.Description),
+			db.Course.Semester.Set(input.Semester),
+			db.Course.Year.Set(input.Year),
+			db.Course.PloGroupID.Set(input.PloGroupID),
+			db.Course.TermID.SetIfPresent(input.TermID),
+			db.Course.CatalogCourseID.SetIfPresent(input.CatalogCourseID),
+			db.Course.Version.Increment(1),
+		).Exec(ctx)
+	}, func() (interface{}, error) {
+		return (&queryResolver{r.Resolver}).Course(ctx, id)
+	})
+	if err != nil {
+		return &model.Course{}, err
+	}
+	return (&queryResolver{r.Resolver}).Course(ctx, id)
+}
+
+func (r *mutationResolver) CloneCourse(ctx context.Context, courseID string, semester int, year int, options *model.CloneCourseOptions, idempotencyKey *string) (*model.CloneCourseResult, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseEditors); err != nil {
+		return &model.CloneCourseResult{}, err
+	}
+	if options == nil {
+		options = &model.CloneCourseOptions{}
+	}
+	created := &model.CloneCourseResult{}
+	err := r.idempotent(ctx, idempotencyKey, "cloneCourse", []interface{}{courseID, semester, year, options}, created, func() error {
+		teacherID, ok := ctx.Value("user_id").(string)
+		if !ok || teacherID == "" {
+			return errors.New("user not found")
+		}
+		source, err := r.Client.Course.FindFirst(
+			append(liveCourses(), db.Course.ID.Equals(courseID))...,
+		).With(
+			db.Course.Los.Fetch().With(
+				db.LO.Levels.Fetch(),
+				db.LO.Links.Fetch(),
+			),
+			db.Course.Quizzes.Fetch(
+				db.Quiz.DeletedAt.IsNull(),
+			).With(
+				db.Quiz.Questions.Fetch().With(
+					db.Question.Links.Fetch(),
 				),
 			),
-		).Delete().Exec(ctx)
+			db.Course.CategoryWeights.Fetch(),
+			db.Course.GradeScheme.Fetch(),
+		).Exec(ctx)
 		if err != nil {
-			return &model.Course{}, err
+			return err
+		}
+		ploGroupID, _ := source.PloGroupID()
+		catalogCourseID, hasCatalogCourse := source.CatalogCourseID()
+		input := model.CreateCourseInput{
+			Name:        source.Name,
+			Description: source.Description,
+			Semester:    semester,
+			Year:        year,
+			PloGroupID:  ploGroupID,
+			TermID:      options.TermID,
+		}
+		if options.Name != nil {
+			input.Name = *options.Name
+		}
+		if hasCatalogCourse {
+			input.CatalogCourseID = &catalogCourseID
+		}
+		termParams, err := r.courseTermParams(ctx, &input)
+		if err != nil {
+			return err
+		}
+		catalogParams, err := r.courseCatalogParams(ctx, input, source.ProgramID)
+		if err != nil {
+			return err
 		}
+
+		includeQuizzes := options.IncludeQuizzes == nil || *options.IncludeQuizzes
+		transactions, result := r.cloneCourseTx(*source, teacherID, input, append(termParams, catalogParams...), includeQuizzes)
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		course, err := (&queryResolver{r.Resolver}).Course(ctx, result.Course.ID)
+		if err != nil {
+			return err
+		}
+		result.Course = course
+		*created = *result
+		return nil
+	})
+	if err != nil {
+		return &model.CloneCourseResult{}, err
 	}
-	updated, err := r.Client.Course.FindUnique(
-		db.Course.ID.Equals(id),
-	).Update(
-		db.Course.Name.Set(input.Name),
-		db.Course.Description.Set(input.Description),
-		db.Course.Semester.Set(input.Semester),
-		db.Course.Year.Set(input.Year),
-		db.Course.PloGroup.Link(
-			db.PLOgroup.ID.Equals(input.PloGroupID),
+	return created, nil
+}
+
+func (r *mutationResolver) RemapLOLinks(ctx context.Context, courseID string, ploGroupID string, rules []*model.PLORemapRule, useRevisionMapping *bool, preview *bool, expectedVersion *int) (*model.RemapLOLinksResult, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return &model.RemapLOLinksResult{}, err
+	}
+	course, err := r.Client.Course.FindFirst(
+		append(liveCourses(), db.Course.ID.Equals(courseID))...,
+	).With(
+		db.Course.Los.Fetch().With(
+			db.LO.Links.Fetch(),
 		),
 	).Exec(ctx)
 	if err != nil {
-		return &model.Course{}, err
+		return &model.RemapLOLinksResult{}, err
 	}
-	ploGroupID, _ = updated.PloGroupID()
-	teacherID, _ := updated.TeacherID()
-	return &model.Course{
-		ID:          updated.ID,
-		Name:        updated.Name,
-		Description: updated.Description,
-		Semester:    updated.Semester,
-		Year:        updated.Year,
-		PloGroupID:  ploGroupID,
-		TeacherID:   teacherID,
-	}, nil
+	if expectedVersion != nil && course.Version != *expectedVersion {
+		current, err := (&queryResolver{r.Resolver}).Course(ctx, courseID)
+		if err != nil {
+			return &model.RemapLOLinksResult{}, err
+		}
+		return &model.RemapLOLinksResult{}, conflictError(current)
+	}
+	transactions, result, err := r.remapLOLinks(ctx, *course, ploGroupID, rules, useRevisionMapping != nil && *useRevisionMapping)
+	if err != nil {
+		return &model.RemapLOLinksResult{}, err
+	}
+	if preview != nil && *preview {
+		return result, nil
+	}
+	// the course is locked and moved at the version after the one it was read
+	// at, so links changed since then make the move and with it the remap a
+	// no-op, see remapLOLinks
+	next := course.Version + 1
+	err = updateVersioned(func() (*db.BatchResult, error) {
+		move := r.Client.Course.FindMany(
+			db.Course.ID.Equals(courseID),
+			db.Course.Version.Equals(next),
+		).Update(
+			db.Course.PloGroupID.Set(ploGroupID),
+		).Tx()
+		transactions = append([]transaction.Param{r.bumpCourseVersion(db.Course.ID.Equals(courseID)), move}, transactions...)
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			moved, findErr := r.Client.Course.FindUnique(
+				db.Course.ID.Equals(courseID),
+			).Exec(ctx)
+			if findErr == nil && moved.Version != course.Version {
+				return &db.BatchResult{}, nil
+			}
+			return nil, err
+		}
+		return move.Result(), nil
+	}, func() (interface{}, error) {
+		return (&queryResolver{r.Resolver}).Course(ctx, courseID)
+	})
+	if err != nil {
+		return &model.RemapLOLinksResult{}, err
+	}
+	result.Applied = true
+	return result, nil
 }
 
 func (r *mutationResolver) DeleteCourse(ctx context.Context, id string) (*model.DeleteCourseResult, error) {
-	deleted, err := r.Client.Course.FindUnique(
-		db.Course.ID.Equals(id),
-	).Delete().Exec(ctx)
+	if err := r.requireUnlockedCourse(ctx, id, courseOwners); err != nil {
+		return &model.DeleteCourseResult{}, err
+	}
+	now := time.Now()
+	trashed, err := r.setDeletedAt(ctx, model.TrashTypeCourse, id, &now)
 	if err != nil {
 		return &model.DeleteCourseResult{}, err
 	}
 	return &model.DeleteCourseResult{
-		ID: deleted.ID,
+		ID: trashed.ID,
 	}, nil
 }
 
-func (r *mutationResolver) CreateLOs(ctx context.Context, courseID string, input []*model.CreateLOsInput) ([]*model.CreateLOResult, error) {
-	result := []*model.CreateLOResult{}
-	for _, loInput := range input {
-		createdLO, err := r.Client.LO.CreateOne(
-			db.LO.Title.Set(loInput.Title),
-			db.LO.Course.Link(
-				db.Course.ID.Equals(courseID),
-			),
-		).Exec(ctx)
-		if err != nil {
-			return []*model.CreateLOResult{}, err
+func (r *mutationResolver) CreateLOs(ctx context.Context, courseID string, input []*model.CreateLOsInput, idempotencyKey *string) ([]*model.CreateLOResult, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return []*model.CreateLOResult{}, err
+	}
+	created := []*model.CreateLOResult{}
+	err := r.idempotent(ctx, idempotencyKey, "createLOs", []interface{}{courseID, input}, &created, func() error {
+		if _, err := r.Client.Course.FindUnique(
+			db.Course.ID.Equals(courseID),
+		).Exec(ctx); err != nil {
+			return err
 		}
-		for _, loLevelInput := range loInput.Levels {
-			r.CreateLOLevel(ctx, createdLO.ID, *loLevelInput)
+		errs := inputErrors{}
+		for i, loInput := range input {
+			seen := map[int]bool{}
+			for j, loLevelInput := range loInput.Levels {
+				if seen[loLevelInput.Level] {
+					errs.add(fmt.Sprintf("input[%d].levels[%d]", i, j), "level %d is listed more than once", loLevelInput.Level)
+				}
+				seen[loLevelInput.Level] = true
+			}
 		}
-		result = append(result, &model.CreateLOResult{
-			ID: createdLO.ID,
-		})
+		if err := errs.err(); err != nil {
+			return err
+		}
+		result := []*model.CreateLOResult{}
+		transactions := []transaction.Param{}
+		for _, loInput := range input {
+			loID := uuid.New().String()
+			transactions = append(transactions, r.Client.LO.CreateOne(
+				db.LO.Title.Set(loInput.Title),
+				db.LO.Course.Link(
+					db.Course.ID.Equals(courseID),
+				),
+				db.LO.ID.Set(loID),
+			).Tx())
+			for _, loLevelInput := range loInput.Levels {
+				transactions = append(transactions, r.Client.LOlevel.CreateOne(
+					db.LOlevel.Level.Set(loLevelInput.Level),
+					db.LOlevel.Description.Set(loLevelInput.Description),
+					db.LOlevel.Lo.Link(
+						db.LO.ID.Equals(loID),
+					),
+				).Tx())
+			}
+			result = append(result, &model.CreateLOResult{
+				ID: loID,
+			})
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		created = result
+		return nil
+	})
+	if err != nil {
+		return []*model.CreateLOResult{}, err
 	}
-	return result, nil
+	return created, nil
 }
 
-func (r *mutationResolver) EditLo(ctx context.Context, id string, title string) (*model.EditLOResult, error) {
+func (r *mutationResolver) EditLo(ctx context.Context, id string, title string, expectedVersion *int) (*model.EditLOResult, error) {
+	if err := r.requireLORole(ctx, id, courseEditors); err != nil {
+		return &model.EditLOResult{}, err
+	}
+	err := updateVersioned(func() (*db.BatchResult, error) {
+		return r.Client.LO.FindMany(
+			db.LO.ID.Equals(id),
+			db.LO.Version.EqualsIfPresent(expectedVersion),
+		).Update(
+			db.LO.Title.Set(title),
+			db.LO.Version.Increment(1),
+		).Exec(ctx)
+	}, func() (interface{}, error) {
+		return (&queryResolver{r.Resolver}).loNode(ctx, id)
+	})
+	if err != nil {
+		return &model.EditLOResult{}, err
+	}
 	updated, err := r.Client.LO.FindUnique(
 		db.LO.ID.Equals(id),
-	).Update(
-		db.LO.Title.Set(title),
 	).Exec(ctx)
 	if err != nil {
 		return &model.EditLOResult{}, err
 	}
 	return &model.EditLOResult{
-		ID: updated.ID,
+		ID:      updated.ID,
+		Version: updated.Version,
 	}, nil
 }
 
 func (r *mutationResolver) EditLOLevel(ctx context.Context, id string, level int, description string) (*model.EditLOLevelResult, error) {
+	if err := r.requireLORole(ctx, id, courseEditors); err != nil {
+		return &model.EditLOLevelResult{}, err
+	}
 	updated, err := r.Client.LOlevel.FindUnique(
 		db.LOlevel.LoIDLevel(
 			db.LOlevel.LoID.Equals(id),
@@ -158,50 +411,85 @@ func (r *mutationResolver) EditLOLevel(ctx context.Context, id string, level int
 	}, nil
 }
 
-func (r *mutationResolver) CreateLOLink(ctx context.Context, loID string, ploID string) (*model.CreateLOLinkResult, error) {
-	createdLO, err := r.Client.LOlink.CreateOne(
+func (r *mutationResolver) CreateLOLink(ctx context.Context, loID string, ploID string, weight *float64, contribution *model.PLOContribution) (*model.CreateLOLinkResult, error) {
+	if err := r.requireLORole(ctx, loID, courseEditors); err != nil {
+		return &model.CreateLOLinkResult{}, err
+	}
+	params := []db.LOlinkSetParam{}
+	if weight != nil {
+		if err := validateLinkWeight(*weight); err != nil {
+			return &model.CreateLOLinkResult{}, err
+		}
+		params = append(params, db.LOlink.Weight.Set(*weight))
+	}
+	if contribution != nil {
+		params = append(params, db.LOlink.Contribution.Set(db.PLOContribution(*contribution)))
+	}
+	create := r.Client.LOlink.CreateOne(
 		db.LOlink.Lo.Link(
 			db.LO.ID.Equals(loID),
 		),
 		db.LOlink.Plo.Link(
 			db.PLO.ID.Equals(ploID),
 		),
-	).Exec(ctx)
-	if err != nil {
+		params...,
+	).Tx()
+	if err := r.Client.Prisma.Transaction(
+		r.bumpCourseVersion(db.Course.Los.Some(db.LO.ID.Equals(loID))),
+		create,
+	).Exec(ctx); err != nil {
 		return &model.CreateLOLinkResult{}, err
 	}
+	createdLO := create.Result()
 	return &model.CreateLOLinkResult{
-		LoID:  createdLO.LoID,
-		PloID: createdLO.PloID,
+		LoID:         createdLO.LoID,
+		PloID:        createdLO.PloID,
+		Weight:       createdLO.Weight,
+		Contribution: linkContribution(*createdLO),
 	}, nil
 }
 
-func (r *mutationResolver) CreateLo(ctx context.Context, courseID string, input model.CreateLOInput) (*model.CreateLOResult, error) {
-	createdLO, err := r.Client.LO.CreateOne(
-		db.LO.Title.Set(input.Title),
-		db.LO.Course.Link(
-			db.Course.ID.Equals(courseID),
-		),
-	).Exec(ctx)
-	if err != nil {
+func (r *mutationResolver) CreateLo(ctx context.Context, courseID string, input model.CreateLOInput, idempotencyKey *string) (*model.CreateLOResult, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
 		return &model.CreateLOResult{}, err
 	}
-	_, err = r.Client.LOlevel.CreateOne(
-		db.LOlevel.Level.Set(input.Level),
-		db.LOlevel.Description.Set(input.Description),
-		db.LOlevel.Lo.Link(
-			db.LO.ID.Equals(createdLO.ID),
-		),
-	).Exec(ctx)
+	created := &model.CreateLOResult{}
+	err := r.idempotent(ctx, idempotencyKey, "createLO", []interface{}{courseID, input}, created, func() error {
+		loID := uuid.New().String()
+		transactions := []transaction.Param{
+			r.Client.LO.CreateOne(
+				db.LO.Title.Set(input.Title),
+				db.LO.Course.Link(
+					db.Course.ID.Equals(courseID),
+				),
+				db.LO.ID.Set(loID),
+			).Tx(),
+			r.Client.LOlevel.CreateOne(
+				db.LOlevel.Level.Set(input.Level),
+				db.LOlevel.Description.Set(input.Description),
+				db.LOlevel.Lo.Link(
+					db.LO.ID.Equals(loID),
+				),
+			).Tx(),
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		*created = model.CreateLOResult{
+			ID: loID,
+		}
+		return nil
+	})
 	if err != nil {
 		return &model.CreateLOResult{}, err
 	}
-	return &model.CreateLOResult{
-		ID: createdLO.ID,
-	}, nil
+	return created, nil
 }
 
 func (r *mutationResolver) CreateLOLevel(ctx context.Context, loID string, input model.CreateLOLevelInput) (*model.CreateLOResult, error) {
+	if err := r.requireLORole(ctx, loID, courseEditors); err != nil {
+		return &model.CreateLOResult{}, err
+	}
 	createdLOLevel, err := r.Client.LOlevel.CreateOne(
 		db.LOlevel.Level.Set(input.Level),
 		db.LOlevel.Description.Set(input.Description),
@@ -218,6 +506,9 @@ func (r *mutationResolver) CreateLOLevel(ctx context.Context, loID string, input
 }
 
 func (r *mutationResolver) DeleteLo(ctx context.Context, id string) (*model.DeleteLOResult, error) {
+	if err := r.requireLORole(ctx, id, courseEditors); err != nil {
+		return &model.DeleteLOResult{}, err
+	}
 	deleted, err := r.Client.LO.FindUnique(
 		db.LO.ID.Equals(id),
 	).Delete().Exec(ctx)
@@ -230,6 +521,9 @@ func (r *mutationResolver) DeleteLo(ctx context.Context, id string) (*model.Dele
 }
 
 func (r *mutationResolver) DeleteLOLevel(ctx context.Context, id string, level int) (*model.DeleteLOLevelResult, error) {
+	if err := r.requireLORole(ctx, id, courseEditors); err != nil {
+		return &model.DeleteLOLevelResult{}, err
+	}
 	deleted, err := r.Client.LOlevel.FindUnique(
 		db.LOlevel.LoIDLevel(
 			db.LOlevel.LoID.Equals(id),
@@ -245,31 +539,169 @@ func (r *mutationResolver) DeleteLOLevel(ctx context.Context, id string, level i
 }
 
 func (r *mutationResolver) DeleteLOLink(ctx context.Context, loID string, ploID string) (*model.DeleteLOLinkResult, error) {
-	deleted, err := r.Client.LOlink.FindUnique(
+	if err := r.requireLORole(ctx, loID, courseEditors); err != nil {
+		return &model.DeleteLOLinkResult{}, err
+	}
+	remove := r.Client.LOlink.FindUnique(
 		db.LOlink.LoIDPloID(
 			db.LOlink.LoID.Equals(loID),
 			db.LOlink.PloID.Equals(ploID),
 		),
-	).Delete().Exec(ctx)
-	if err != nil {
+	).Delete().Tx()
+	if err := r.Client.Prisma.Transaction(
+		r.bumpCourseVersion(db.Course.Los.Some(db.LO.ID.Equals(loID))),
+		remove,
+	).Exec(ctx); err != nil {
 		return &model.DeleteLOLinkResult{}, err
 	}
+	deleted := remove.Result()
 	return &model.DeleteLOLinkResult{
 		LoID:  deleted.LoID,
 		PloID: deleted.PloID,
 	}, nil
 }
 
-func (r *queryResolver) Courses(ctx context.Context, programID string) ([]*model.Course, error) {
+func (r *mutationResolver) AddCourseStaff(ctx context.Context, courseID string, teacherID string, role model.CourseStaffRole) (*model.CourseStaff, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseOwners); err != nil {
+		return &model.CourseStaff{}, err
+	}
+	teacher, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(teacherID),
+	).With(
+		db.Teacher.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CourseStaff{}, err
+	}
+	if !teacher.Active {
+		return &model.CourseStaff{}, errors.New("teacher account is deactivated")
+	}
+	staff, err := r.courseStaff(ctx, courseID)
+	if err != nil {
+		return &model.CourseStaff{}, err
+	}
+	if staffMember(staff, teacherID) != nil {
+		return &model.CourseStaff{}, errors.New("teacher is already on the course staff")
+	}
+	created, err := r.Client.CourseStaff.CreateOne(
+		db.CourseStaff.Course.Link(
+			db.Course.ID.Equals(courseID),
+		),
+		db.CourseStaff.Teacher.Link(
+			db.Teacher.ID.Equals(teacherID),
+		),
+		db.CourseStaff.Role.Set(db.CourseStaffRole(role)),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CourseStaff{}, err
+	}
+	return &model.CourseStaff{
+		CourseID:  created.CourseID,
+		TeacherID: created.TeacherID,
+		Teacher: &model.User{
+			ID:      teacher.ID,
+			Email:   teacher.User().Email,
+			Name:    teacher.User().Name,
+			Surname: teacher.User().Surname,
+		},
+		Role: model.CourseStaffRole(created.Role),
+	}, nil
+}
+
+func (r *mutationResolver) SetCourseStaffRole(ctx context.Context, courseID string, teacherID string, role model.CourseStaffRole) (*model.CourseStaff, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseOwners); err != nil {
+		return &model.CourseStaff{}, err
+	}
+	staff, err := r.courseStaff(ctx, courseID)
+	if err != nil {
+		return &model.CourseStaff{}, err
+	}
+	member := staffMember(staff, teacherID)
+	if member == nil {
+		return &model.CourseStaff{}, errors.New("teacher isn't on the course staff")
+	}
+	if role != model.CourseStaffRoleOwner && otherOwner(staff, teacherID) == nil {
+		return &model.CourseStaff{}, errors.New("a course must keep at least one owner")
+	}
+	// the owner implied by teacherID has no record yet, so upsert
+	if _, err := r.Client.CourseStaff.UpsertOne(
+		db.CourseStaff.CourseIDTeacherID(
+			db.CourseStaff.CourseID.Equals(courseID),
+			db.CourseStaff.TeacherID.Equals(teacherID),
+		),
+	).Create(
+		db.CourseStaff.Course.Link(
+			db.Course.ID.Equals(courseID),
+		),
+		db.CourseStaff.Teacher.Link(
+			db.Teacher.ID.Equals(teacherID),
+		),
+		db.CourseStaff.Role.Set(db.CourseStaffRole(role)),
+	).Update(
+		db.CourseStaff.Role.Set(db.CourseStaffRole(role)),
+	).Exec(ctx); err != nil {
+		return &model.CourseStaff{}, err
+	}
+	member.Role = role
+	return member, nil
+}
+
+func (r *mutationResolver) RemoveCourseStaff(ctx context.Context, courseID string, teacherID string) (*model.RemoveCourseStaffResult, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseOwners); err != nil {
+		return &model.RemoveCourseStaffResult{}, err
+	}
+	staff, err := r.courseStaff(ctx, courseID)
+	if err != nil {
+		return &model.RemoveCourseStaffResult{}, err
+	}
+	if staffMember(staff, teacherID) == nil {
+		return &model.RemoveCourseStaffResult{}, errors.New("teacher isn't on the course staff")
+	}
+	owner := otherOwner(staff, teacherID)
+	if owner == nil {
+		return &model.RemoveCourseStaffResult{}, errors.New("a course must keep at least one owner")
+	}
+	if _, err := r.Client.CourseStaff.FindMany(
+		db.CourseStaff.CourseID.Equals(courseID),
+		db.CourseStaff.TeacherID.Equals(teacherID),
+	).Delete().Exec(ctx); err != nil {
+		return &model.RemoveCourseStaffResult{}, err
+	}
+	if _, err := r.Client.SectionStaff.FindMany(
+		db.SectionStaff.TeacherID.Equals(teacherID),
+		db.SectionStaff.Section.Where(
+			db.Section.CourseID.Equals(courseID),
+		),
+	).Delete().Exec(ctx); err != nil {
+		return &model.RemoveCourseStaffResult{}, err
+	}
+	// teacherID would otherwise keep the removed teacher as owner
+	if _, err := r.Client.Course.FindMany(
+		db.Course.ID.Equals(courseID),
+		db.Course.TeacherID.Equals(teacherID),
+	).Update(
+		db.Course.Teacher.Link(
+			db.Teacher.ID.Equals(owner.TeacherID),
+		),
+	).Exec(ctx); err != nil {
+		return &model.RemoveCourseStaffResult{}, err
+	}
+	return &model.RemoveCourseStaffResult{
+		CourseID:  courseID,
+		TeacherID: teacherID,
+	}, nil
+}
+
+func (r *queryResolver) Courses(ctx context.Context, programID string, termID *string) ([]*model.Course, error) {
 	var allCourses []db.CourseModel
 	var err error
 	if programID == "" {
-		allCourses, err = r.Client.Course.FindMany().Exec(ctx)
+		allCourses, err = r.Client.Course.FindMany(
+			append(liveCourses(), db.Course.TermID.EqualsIfPresent(termID))...,
+		).Exec(ctx)
 	} else {
 		allCourses, err = r.Client.Course.FindMany(
-			db.Course.Program.Where(
-				db.Program.ID.Equals(programID),
-			),
+			append(liveCourses(db.Program.ID.Equals(programID)), db.Course.TermID.EqualsIfPresent(termID))...,
 		).Exec(ctx)
 	}
 	if err != nil {
@@ -288,14 +720,15 @@ func (r *queryResolver) Courses(ctx context.Context, programID string) ([]*model
 			PloGroupID:  ploGroupID,
 			ProgramID:   course.ProgramID,
 			TeacherID:   teacherID,
+			Version:     course.Version,
 		})
 	}
 	return courses, nil
 }
 
 func (r *queryResolver) Course(ctx context.Context, courseID string) (*model.Course, error) {
-	course, err := r.Client.Course.FindUnique(
-		db.Course.ID.Equals(courseID),
+	course, err := r.Client.Course.FindFirst(
+		append(liveCourses(), db.Course.ID.Equals(courseID))...,
 	).Exec(ctx)
 	if err != nil {
 		return &model.Course{}, err
@@ -311,13 +744,14 @@ func (r *queryResolver) Course(ctx context.Context, courseID string) (*model.Cou
 		PloGroupID:  ploGroupID,
 		ProgramID:   course.ProgramID,
 		TeacherID:   teacherID,
+		Version:     course.Version,
 	}, nil
 }
 
 func (r *queryResolver) Los(ctx context.Context, courseID string) ([]*model.Lo, error) {
 	allLOs, err := r.Client.LO.FindMany(
 		db.LO.Course.Where(
-			db.Course.ID.Equals(courseID),
+			append(liveCourses(), db.Course.ID.Equals(courseID))...,
 		),
 	).With(
 		db.LO.Levels.Fetch(),
@@ -333,38 +767,36 @@ func (r *queryResolver) Los(ctx context.Context, courseID string) ([]*model.Lo,
 		levels := []*model.LOLevel{}
 		for _, level := range lo.Levels() {
 			levels = append(levels, &model.LOLevel{
+				LoID:        level.LoID,
 				Level:       level.Level,
 				Description: level.Description,
 			})
 		}
-		ploLinks := []*model.Plo{}
-		for _, plo := range lo.Links() {
-			ploLinks = append(ploLinks, &model.Plo{
-				ID:          plo.PloID,
-				Title:       plo.Plo().Title,
-				Description: plo.Plo().Description,
-				PloGroupID:  plo.Plo().PloGroupID,
-			})
+		ploLinks := []*model.LOPLOLink{}
+		for _, link := range lo.Links() {
+			ploLinks = append(ploLinks, loLinkModel(link))
 		}
 		los = append(los, &model.Lo{
 			ID:       lo.ID,
 			Title:    lo.Title,
 			Levels:   levels,
 			PloLinks: ploLinks,
+			Version:  lo.Version,
 		})
 	}
 	return los, nil
 }
 
-func (r *queryResolver) StudentsInCourse(ctx context.Context, courseID string) ([]*model.User, error) {
+func (r *queryResolver) StudentsInCourse(ctx context.Context, courseID string, sectionID *string) ([]*model.User, error) {
 	allStudents, err := r.Client.User.FindMany(
 		db.User.Student.Where(
-			db.Student.QuestionResults.Some(
-				db.QuestionResult.Question.Where(
-					db.Question.Quiz.Where(
-						db.Quiz.CourseID.Equals(courseID),
+			db.Student.Enrollments.Some(
+				activeEnrollment(
+					db.Enrollment.Course.Where(
+						append(liveCourses(), db.Course.ID.Equals(courseID))...,
 					),
-				),
+					db.Enrollment.SectionID.EqualsIfPresent(sectionID),
+				)...,
 			),
 		),
 	).Exec(ctx)
@@ -383,11 +815,31 @@ func (r *queryResolver) StudentsInCourse(ctx context.Context, courseID string) (
 	return students, nil
 }
 
+func (r *userResolver) ID(ctx context.Context, obj *model.User) (string, error) {
+	return encodeNodeID("User", obj.ID), nil
+}
+
+// Course returns generated.CourseResolver implementation.
+func (r *Resolver) Course() generated.CourseResolver { return &courseResolver{r} }
+
+// LO returns generated.LOResolver implementation.
+func (r *Resolver) LO() generated.LOResolver { return &lOResolver{r} }
+
+// LOLevel returns generated.LOLevelResolver implementation.
+func (r *Resolver) LOLevel() generated.LOLevelResolver { return &lOLevelResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type courseResolver struct{ *Resolver }
+type lOResolver struct{ *Resolver }
+type lOLevelResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
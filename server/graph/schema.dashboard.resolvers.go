@@ -9,14 +9,20 @@ import (
 	"context"
 	"sort"
 	"strconv"
+
+	"github.com/shopspring/decimal"
 )
 
-func (r *queryResolver) QuizResults(ctx context.Context, courseID string) ([]*model.DashboardResult, error) {
+func (r *queryResolver) QuizResults(ctx context.Context, courseID string, sectionID *string) ([]*model.DashboardResult, error) {
+	results, err := r.courseResults(ctx, courseID)
+	if err != nil {
+		return []*model.DashboardResult{}, err
+	}
 	allQuizzes, err := r.Client.Quiz.FindMany(
-		db.Quiz.CourseID.Equals(courseID),
+		append(liveQuizzes(), db.Quiz.CourseID.Equals(courseID))...,
 	).With(
 		db.Quiz.Questions.Fetch().With(
-			db.Question.Results.Fetch().With(
+			db.Question.Results.Fetch(append(results, enrolledResult(courseID, sectionID))...).With(
 				db.QuestionResult.Student.Fetch().With(
 					db.Student.User.Fetch(),
 				),
@@ -26,30 +32,46 @@ func (r *queryResolver) QuizResults(ctx context.Context, courseID string) ([]*mo
 	if err != nil {
 		return []*model.DashboardResult{}, err
 	}
+	sections, err := r.studentSections(ctx, courseID)
+	if err != nil {
+		return []*model.DashboardResult{}, err
+	}
+	weights, err := r.Client.CategoryWeight.FindMany(
+		db.CategoryWeight.CourseID.Equals(courseID),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.DashboardResult{}, err
+	}
+	grades := newGradeBook(allQuizzes, weights)
 	response := []*model.DashboardResult{}
 	for _, quiz := range allQuizzes {
-		maxScore := 0
+		maxScore := decimal.Zero
 		studentScore := map[string]*model.DashboardResultSub{}
 		for _, question := range quiz.Questions() {
-			maxScore += question.MaxScore
+			maxScore = maxScore.Add(question.MaxScore)
 			for _, result := range question.Results() {
 				if _, added := studentScore[result.StudentID]; !added {
 					studentScore[result.StudentID] = &model.DashboardResultSub{
 						StudentID:    result.StudentID,
+						SectionID:    sections[result.StudentID],
 						StudentName:  result.Student().User().Name,
-						StudentScore: 0,
+						StudentScore: decimal.Zero,
 					}
 				}
-				studentScore[result.StudentID].StudentScore += result.Score
+				studentScore[result.StudentID].StudentScore = studentScore[result.StudentID].StudentScore.Add(result.Score)
 			}
 		}
+		share := grades.shares[quiz.ID]
 		results := []*model.DashboardResultSub{}
 		for _, result := range studentScore {
+			result.WeightedScore = share * scorePercentage(result.StudentScore, maxScore)
 			results = append(results, result)
 		}
 		response = append(response, &model.DashboardResult{
 			QuizName: quiz.Name,
 			MaxScore: maxScore,
+			Category: model.AssessmentCategory(quiz.Category),
+			Share:    share,
 			Results:  results,
 		})
 	}
@@ -84,14 +106,22 @@ func (r *queryResolver) PloSummary(ctx context.Context, courseID string) ([]*mod
 	return response, nil
 }
 
-func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*model.DashboardFlat, error) {
-	students, err := r.StudentsInCourse(ctx, courseID)
+func (r *queryResolver) FlatSummary(ctx context.Context, courseID string, sectionID *string) (*model.DashboardFlat, error) {
+	results, err := r.courseResults(ctx, courseID)
 	if err != nil {
-		return &model.DashboardFlat{}, nil
+		return &model.DashboardFlat{}, err
+	}
+	students, err := r.StudentsInCourse(ctx, courseID, sectionID)
+	if err != nil {
+		return &model.DashboardFlat{}, err
+	}
+	sections, err := r.studentSections(ctx, courseID)
+	if err != nil {
+		return &model.DashboardFlat{}, err
 	}
 	allQuestions, err := r.Client.Question.FindMany(
 		db.Question.Quiz.Where(
-			db.Quiz.CourseID.Equals(courseID),
+			append(liveQuizzes(), db.Quiz.CourseID.Equals(courseID))...,
 		),
 	).With(
 		db.Question.Links.Fetch().With(
@@ -101,10 +131,10 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 				),
 			),
 		),
-		db.Question.Results.Fetch(),
+		db.Question.Results.Fetch(append(results, enrolledResult(courseID, sectionID))...),
 	).Exec(ctx)
 	if err != nil {
-		return &model.DashboardFlat{}, nil
+		return &model.DashboardFlat{}, err
 	}
 	response := &model.DashboardFlat{
 		Students:  students,
@@ -118,13 +148,21 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 		results := []*model.DashboardFlatQuestionResult{}
 		includedPLOsLocal := map[string]bool{}
 		includedLOsLocal := map[string]bool{}
+		linkedLOLevels := []*model.LOLevel{}
+		linkShares := []*model.DashboardFlatQuestionLinkShare{}
 		for _, result := range question.Results() {
 			results = append(results, &model.DashboardFlatQuestionResult{
 				StudentID:    result.StudentID,
+				SectionID:    sections[result.StudentID],
 				StudentScore: result.Score,
 			})
 		}
 		for _, lo := range question.Links() {
+			linkShares = append(linkShares, &model.DashboardFlatQuestionLinkShare{
+				LoID:  lo.LoID,
+				Level: lo.Level,
+				Share: linkShare(question, lo),
+			})
 			for _, link := range lo.LoLevel().Lo().Links() {
 				ploID := link.Plo().ID
 				if _, addedLocal := includedPLOsLocal[ploID]; !addedLocal {
@@ -135,6 +173,7 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 							Title:       link.Plo().Title,
 							Description: link.Plo().Description,
 							PloGroupID:  link.Plo().PloGroupID,
+							Version:     link.Plo().Version,
 						})
 					}
 				}
@@ -143,11 +182,13 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 			loLevel := strconv.Itoa(lo.LoLevel().Level)
 			if _, addedLocal := includedLOsLocal[loID+","+loLevel]; !addedLocal {
 				includedLOsLocal[loID+","+loLevel] = true
+				linkedLOLevels = append(linkedLOLevels, &model.LOLevel{LoID: loID, Level: lo.LoLevel().Level, Description: lo.LoLevel().Description})
 				if _, addedGlobal := includedLOsGlobal[loID]; !addedGlobal {
 					includedLOsGlobal[loID] = &model.Lo{
-						ID:     lo.LoLevel().LoID,
-						Title:  lo.LoLevel().Lo().Title,
-						Levels: []*model.LOLevel{{Level: lo.LoLevel().Level, Description: lo.LoLevel().Description}},
+						ID:      lo.LoLevel().LoID,
+						Title:   lo.LoLevel().Lo().Title,
+						Levels:  []*model.LOLevel{{LoID: loID, Level: lo.LoLevel().Level, Description: lo.LoLevel().Description}},
+						Version: lo.LoLevel().Lo().Version,
 					}
 				} else {
 					found := false
@@ -159,7 +200,7 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 					}
 					if !found {
 						temp := includedLOsGlobal[lo.LoLevel().LoID]
-						temp.Levels = append(temp.Levels, &model.LOLevel{Level: lo.LoLevel().Level, Description: lo.LoLevel().Description})
+						temp.Levels = append(temp.Levels, &model.LOLevel{LoID: loID, Level: lo.LoLevel().Level, Description: lo.LoLevel().Description})
 						includedLOsGlobal[lo.LoLevel().LoID] = temp
 					}
 				}
@@ -174,11 +215,13 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 			linkedLOs = append(linkedLOs, loIDLevel)
 		}
 		response.Questions = append(response.Questions, &model.DashboardFlatQuestion{
-			Title:      question.Title,
-			MaxScore:   question.MaxScore,
-			LinkedPLOs: linkedPLOs,
-			LinkedLOs:  linkedLOs,
-			Results:    results,
+			Title:          question.Title,
+			MaxScore:       question.MaxScore,
+			LinkedPLOs:     linkedPLOs,
+			LinkedLOs:      linkedLOs,
+			LinkedLOLevels: linkedLOLevels,
+			LinkShares:     linkShares,
+			Results:        results,
 		})
 	}
 	for _, lo := range includedLOsGlobal {
@@ -187,11 +230,21 @@ func (r *queryResolver) FlatSummary(ctx context.Context, courseID string) (*mode
 	return response, nil
 }
 
-func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string) (*model.DashboardIndividual, error) {
+func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string, termID *string, ploGroupID *string) (*model.DashboardIndividual, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return &model.DashboardIndividual{}, err
+	}
+	if !viewer.IsTeacher && viewer.ID != studentID {
+		return &model.DashboardIndividual{}, errPermissionDenied
+	}
 	allQuestionResults, err := r.Client.QuestionResult.FindMany(
 		db.QuestionResult.Student.Where(
 			db.Student.ID.Equals(studentID),
 		),
+		db.QuestionResult.Question.Where(
+			db.Question.Quiz.Where(liveQuizzes(db.Course.TermID.EqualsIfPresent(termID))...),
+		),
 	).With(
 		db.QuestionResult.Question.Fetch().With(
 			db.Question.Quiz.Fetch().With(
@@ -213,20 +266,27 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 	if err != nil {
 		return &model.DashboardIndividual{}, err
 	}
+	var translator *ploTranslator
+	if ploGroupID != nil {
+		translator, err = r.newPLOTranslator(ctx, *ploGroupID)
+		if err != nil {
+			return &model.DashboardIndividual{}, err
+		}
+	}
 	type CustomPLOGroup struct {
 		Name string
 		Plos map[string]*model.DashboardIndividualPlo
 	}
 	courseMap := map[string]*model.DashboardIndividualCourse{}
 	ploGroupMap := map[string]*CustomPLOGroup{}
-	loCount := map[string]int{}
-	ploCount := map[string]int{}
+	loWeight := map[string]float64{}
+	ploWeight := map[string]float64{}
 
 	for _, result := range allQuestionResults {
 		var idvCourse *model.DashboardIndividualCourse
 		question := result.Question()
 		quiz := question.Quiz()
-		thisPercent := float64(result.Score) / float64(question.MaxScore)
+		thisPercent := scorePercentage(result.Score, question.MaxScore)
 
 		course := quiz.Course()
 		if val, ok := courseMap[course.ID]; ok {
@@ -247,8 +307,8 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 		for _, val := range idvQuizzes {
 			if val.ID == quiz.ID {
 				idvQuiz = val
-				idvQuiz.MaxScore += question.MaxScore
-				idvQuiz.StudentScore += result.Score
+				idvQuiz.MaxScore = idvQuiz.MaxScore.Add(question.MaxScore)
+				idvQuiz.StudentScore = idvQuiz.StudentScore.Add(result.Score)
 				quizFound = true
 				break
 			}
@@ -259,11 +319,14 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 				Name:         quiz.Name,
 				MaxScore:     question.MaxScore,
 				StudentScore: result.Score,
+				Category:     model.AssessmentCategory(quiz.Category),
 				Los:          []string{},
+				LoLevels:     []*model.LOLevel{},
 			}
 		}
 
 		for _, qlink := range question.Links() {
+			share := linkShare(*question, qlink)
 			loLevel := qlink.LoLevel().Level
 			loDescription := qlink.LoLevel().Description
 			loTitle := qlink.LoLevel().Lo().Title
@@ -289,8 +352,7 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 							Description: loDescription,
 						})
 					}
-					n := loCount[loID]
-					idvLo.Percentage = (idvLo.Percentage*float64(n) + thisPercent) / float64(n+1)
+					idvLo.Percentage = weightedMean(idvLo.Percentage, loWeight[loID], thisPercent, share)
 					loFound = true
 					break
 				}
@@ -306,7 +368,7 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 					},
 				})
 			}
-			loCount[loID] += 1
+			loWeight[loID] += share
 			// end: Course_LO update
 			// begin: Course_Quiz_Link update
 			found := false
@@ -318,38 +380,52 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 			}
 			if !found {
 				idvQuiz.Los = append(idvQuiz.Los, loID+","+strconv.Itoa(loLevel))
+				idvQuiz.LoLevels = append(idvQuiz.LoLevels, &model.LOLevel{
+					LoID:        loID,
+					Level:       loLevel,
+					Description: loDescription,
+				})
 			}
 			// end: Course_Quiz_Link update
 
 			for _, llink := range qlink.LoLevel().Lo().Links() {
-				ploID := llink.Plo().ID
-				ploTitle := llink.Plo().Title
-				ploDescription := llink.Plo().Description
+				plos := []db.PLOModel{*llink.Plo()}
 				ploGroupName := llink.Plo().PloGroup().Name
 				ploGroupID := llink.Plo().PloGroup().ID
-				// begin: PLO update
-				if _, created := ploGroupMap[ploGroupID]; !created {
-					// create base ploGroup if this ploGroup hasn't been created
-					ploGroupMap[ploGroupID] = &CustomPLOGroup{
-						Name: ploGroupName,
-						Plos: map[string]*model.DashboardIndividualPlo{},
+				if translator != nil && translator.covers(llink.Plo().ID) {
+					// report the PLO as the target revision's PLOs it became
+					plos = []db.PLOModel{}
+					for _, id := range translator.translate(llink.Plo().ID) {
+						plos = append(plos, translator.plos[id])
 					}
+					ploGroupName = translator.target.Name
+					ploGroupID = translator.target.ID
 				}
-				if _, has := ploGroupMap[ploGroupID].Plos[ploID]; !has {
-					// add base PLO to this ploGroup if not exist yet
-					ploGroupMap[ploGroupID].Plos[ploID] = &model.DashboardIndividualPlo{
-						Title:       ploTitle,
-						Description: ploDescription,
-						Percentage:  thisPercent,
+				for _, plo := range plos {
+					ploID := plo.ID
+					// begin: PLO update
+					if _, created := ploGroupMap[ploGroupID]; !created {
+						// create base ploGroup if this ploGroup hasn't been created
+						ploGroupMap[ploGroupID] = &CustomPLOGroup{
+							Name: ploGroupName,
+							Plos: map[string]*model.DashboardIndividualPlo{},
+						}
 					}
-				} else {
-					// if this PLO already exist, update the percentage
-					n := ploCount[ploID]
-					oldPercent := ploGroupMap[ploGroupID].Plos[ploID].Percentage
-					ploGroupMap[ploGroupID].Plos[ploID].Percentage = (oldPercent*float64(n) + thisPercent) / float64(n+1)
+					if _, has := ploGroupMap[ploGroupID].Plos[ploID]; !has {
+						// add base PLO to this ploGroup if not exist yet
+						ploGroupMap[ploGroupID].Plos[ploID] = &model.DashboardIndividualPlo{
+							Title:       plo.Title,
+							Description: plo.Description,
+							Percentage:  thisPercent,
+						}
+					} else {
+						// if this PLO already exist, update the percentage
+						oldPercent := ploGroupMap[ploGroupID].Plos[ploID].Percentage
+						ploGroupMap[ploGroupID].Plos[ploID].Percentage = weightedMean(oldPercent, ploWeight[ploID], thisPercent, share*llink.Weight)
+					}
+					ploWeight[ploID] += share * llink.Weight
+					// end: PLO update
 				}
-				ploCount[ploID] += 1
-				// end: PLO update
 			}
 		}
 		if !quizFound {
@@ -372,7 +448,15 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 		})
 	}
 	courses := []*model.DashboardIndividualCourse{}
-	for _, course := range courseMap {
+	for courseID, course := range courseMap {
+		grades, err := r.gradeBook(ctx, courseID, db.QuestionResult.StudentID.Equals(studentID))
+		if err != nil {
+			return &model.DashboardIndividual{}, err
+		}
+		course.Grade = grades.grade(courseID, studentID).Percentage
+		for _, quiz := range course.Quizzes {
+			quiz.Share = grades.shares[quiz.ID]
+		}
 		courses = append(courses, course)
 	}
 	return &model.DashboardIndividual{
@@ -381,21 +465,28 @@ func (r *queryResolver) IndividualSummary(ctx context.Context, studentID string)
 	}, nil
 }
 
-func (r *queryResolver) IndividualPLOGroupSummary(ctx context.Context, ploGroupID string) (*model.DashboardPLOGroup, error) {
-	ploGroup, err := r.Client.PLOgroup.FindUnique(
-		db.PLOgroup.ID.Equals(ploGroupID),
+func (r *queryResolver) IndividualPLOGroupSummary(ctx context.Context, ploGroupID string, sectionID *string, termID *string, includeRevisions *bool) (*model.DashboardPLOGroup, error) {
+	ploGroup, err := r.Client.PLOgroup.FindFirst(
+		append(livePLOGroups(), db.PLOgroup.ID.Equals(ploGroupID))...,
 	).Exec(ctx)
 	if err != nil {
 		return &model.DashboardPLOGroup{}, err
 	}
+	translator, err := r.newPLOTranslator(ctx, ploGroupID)
+	if err != nil {
+		return &model.DashboardPLOGroup{}, err
+	}
+	ploGroupIDs := []string{ploGroupID}
+	if includeRevisions != nil && *includeRevisions {
+		ploGroupIDs = translator.groups
+	}
 	allQuestionResults, err := r.Client.QuestionResult.FindMany(
 		db.QuestionResult.Question.Where(
 			db.Question.Quiz.Where(
-				db.Quiz.Course.Where(
-					db.Course.PloGroup.Where(
-						db.PLOgroup.ID.Equals(ploGroupID),
-					),
-				),
+				liveQuizzes(
+					db.Course.PloGroupID.In(ploGroupIDs),
+					db.Course.TermID.EqualsIfPresent(termID),
+				)...,
 			),
 		),
 	).With(
@@ -420,49 +511,72 @@ func (r *queryResolver) IndividualPLOGroupSummary(ctx context.Context, ploGroupI
 	if err != nil {
 		return &model.DashboardPLOGroup{}, err
 	}
+	allEnrollments, err := r.Client.Enrollment.FindMany(
+		activeEnrollment(
+			db.Enrollment.Course.Where(
+				append(liveCourses(), db.Course.PloGroupID.In(ploGroupIDs), db.Course.TermID.EqualsIfPresent(termID))...,
+			),
+			db.Enrollment.SectionID.EqualsIfPresent(sectionID),
+		)...,
+	).With(
+		db.Enrollment.Student.Fetch().With(
+			db.Student.User.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.DashboardPLOGroup{}, err
+	}
 	type StudentRecord struct {
 		percentage float64
-		count      float64
+		weight     float64
 	}
 	students := map[string]*model.User{}
+	enrolled := map[string]bool{}
+	for _, enrollment := range allEnrollments {
+		user := enrollment.Student().User()
+		students[user.ID] = &model.User{
+			ID:      user.ID,
+			Email:   user.Email,
+			Name:    user.Name,
+			Surname: user.Surname,
+		}
+		enrolled[enrollment.CourseID+","+user.ID] = true
+	}
 	ploRecords := map[string]map[string]*StudentRecord{}
 	plos := map[string]*model.DashboardPLOGroupDetail{}
 	for _, questionResult := range allQuestionResults {
 		studentID := questionResult.Student().User().ID
-		if _, ok := students[studentID]; !ok {
-			students[studentID] = &model.User{
-				ID:      studentID,
-				Email:   questionResult.Student().User().Email,
-				Name:    questionResult.Student().User().Name,
-				Surname: questionResult.Student().User().Surname,
-			}
+		if !enrolled[questionResult.Question().Quiz().CourseID+","+studentID] {
+			continue
 		}
-		thisPercent := float64(questionResult.Score) / float64(questionResult.Question().MaxScore)
+		thisPercent := scorePercentage(questionResult.Score, questionResult.Question().MaxScore)
 		for _, qlink := range questionResult.Question().Links() {
 			for _, llink := range qlink.LoLevel().Lo().Links() {
-				ploID := llink.Plo().ID
-				ploTitle := llink.Plo().Title
-				ploDescription := llink.Plo().Description
-				if _, ok := plos[ploID]; !ok {
-					plos[ploID] = &model.DashboardPLOGroupDetail{
-						Title:       ploTitle,
-						Description: ploDescription,
-						Stats:       &model.DashboardPLOGroupDetailStats{},
+				// results of other revisions count towards the PLOs they map to
+				for _, ploID := range translator.translate(llink.Plo().ID) {
+					plo := translator.plos[ploID]
+					if _, ok := plos[ploID]; !ok {
+						plos[ploID] = &model.DashboardPLOGroupDetail{
+							Title:       plo.Title,
+							Description: plo.Description,
+							Stats:       &model.DashboardPLOGroupDetailStats{},
+						}
 					}
-				}
-				if _, ok := ploRecords[ploID]; !ok {
-					ploRecords[ploID] = map[string]*StudentRecord{}
-				}
-				if _, ok := ploRecords[ploID][studentID]; !ok {
-					ploRecords[ploID][studentID] = &StudentRecord{
-						percentage: 0,
-						count:      0,
+					if _, ok := ploRecords[ploID]; !ok {
+						ploRecords[ploID] = map[string]*StudentRecord{}
+					}
+					if _, ok := ploRecords[ploID][studentID]; !ok {
+						ploRecords[ploID][studentID] = &StudentRecord{
+							percentage: 0,
+							weight:     0,
+						}
 					}
+					studentRecord := ploRecords[ploID][studentID]
+					weight := linkShare(*questionResult.Question(), qlink) * llink.Weight
+					studentRecord.percentage = weightedMean(studentRecord.percentage, studentRecord.weight, thisPercent, weight)
+					studentRecord.weight += weight
+					ploRecords[ploID][studentID] = studentRecord
 				}
-				studentRecord := ploRecords[ploID][studentID]
-				studentRecord.percentage = (studentRecord.percentage*studentRecord.count + thisPercent) / (studentRecord.count + 1)
-				studentRecord.count += 1
-				ploRecords[ploID][studentID] = studentRecord
 			}
 		}
 	}
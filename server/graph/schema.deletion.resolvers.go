@@ -0,0 +1,41 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (r *queryResolver) DeletionImpact(ctx context.Context, entity model.DeletionEntity, id string, level *int) (*model.DeletionImpact, error) {
+	switch entity {
+	case model.DeletionEntityPlo:
+		if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+			return &model.DeletionImpact{}, err
+		}
+		return r.ploImpact(ctx, id)
+	case model.DeletionEntityPloGroup:
+		if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+			return &model.DeletionImpact{}, err
+		}
+		return r.ploGroupImpact(ctx, id)
+	case model.DeletionEntityLo:
+		if err := r.requireLORole(ctx, id, courseEditors); err != nil {
+			return &model.DeletionImpact{}, err
+		}
+		return r.loImpact(ctx, id)
+	case model.DeletionEntityLoLevel:
+		if err := r.requireLORole(ctx, id, courseEditors); err != nil {
+			return &model.DeletionImpact{}, err
+		}
+		if level == nil {
+			return &model.DeletionImpact{}, errors.New("level is required for LO_LEVEL")
+		}
+		return r.loLevelImpact(ctx, id, *level)
+	default:
+		return &model.DeletionImpact{}, fmt.Errorf("unknown entity %s", entity)
+	}
+}
@@ -0,0 +1,152 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+func (r *mutationResolver) Enroll(ctx context.Context, courseID string, studentID string, status *model.EnrollmentStatus) (*model.Enrollment, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return &model.Enrollment{}, err
+	}
+	if _, err := r.Client.Student.FindUnique(
+		db.Student.ID.Equals(studentID),
+	).Exec(ctx); err != nil {
+		return &model.Enrollment{}, err
+	}
+	if _, err := r.Client.Enrollment.UpsertOne(
+		db.Enrollment.CourseIDStudentID(
+			db.Enrollment.CourseID.Equals(courseID),
+			db.Enrollment.StudentID.Equals(studentID),
+		),
+	).Create(
+		db.Enrollment.Course.Link(
+			db.Course.ID.Equals(courseID),
+		),
+		db.Enrollment.Student.Link(
+			db.Student.ID.Equals(studentID),
+		),
+		db.Enrollment.Status.Set(enrollmentStatus(status)),
+	).Update(
+		db.Enrollment.Status.Set(enrollmentStatus(status)),
+	).Exec(ctx); err != nil {
+		return &model.Enrollment{}, err
+	}
+	enrollments, err := r.enrollments(ctx, courseID, db.Enrollment.StudentID.Equals(studentID))
+	if err != nil || len(enrollments) == 0 {
+		return &model.Enrollment{}, err
+	}
+	return enrollments[0], nil
+}
+
+func (r *mutationResolver) EnrollStudents(ctx context.Context, courseID string, studentIDs []string, status *model.EnrollmentStatus) ([]*model.Enrollment, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return []*model.Enrollment{}, err
+	}
+	if _, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).Exec(ctx); err != nil {
+		return []*model.Enrollment{}, err
+	}
+	existing, err := r.existingStudents(ctx, studentIDs)
+	if err != nil {
+		return []*model.Enrollment{}, err
+	}
+	errs := inputErrors{}
+	seen := map[string]bool{}
+	for i, studentID := range studentIDs {
+		path := fmt.Sprintf("studentIDs[%d]", i)
+		if !existing[studentID] {
+			errs.add(path, "student %s doesn't exist", studentID)
+		} else if seen[studentID] {
+			errs.add(path, "student %s is listed more than once", studentID)
+		}
+		seen[studentID] = true
+	}
+	if err := errs.err(); err != nil {
+		return []*model.Enrollment{}, err
+	}
+	transactions := []transaction.Param{}
+	for _, studentID := range studentIDs {
+		transactions = append(transactions, r.Client.Enrollment.UpsertOne(
+			db.Enrollment.CourseIDStudentID(
+				db.Enrollment.CourseID.Equals(courseID),
+				db.Enrollment.StudentID.Equals(studentID),
+			),
+		).Create(
+			db.Enrollment.Course.Link(
+				db.Course.ID.Equals(courseID),
+			),
+			db.Enrollment.Student.Link(
+				db.Student.ID.Equals(studentID),
+			),
+			db.Enrollment.Status.Set(enrollmentStatus(status)),
+		).Update(
+			db.Enrollment.Status.Set(enrollmentStatus(status)),
+		).Tx())
+	}
+	if len(transactions) > 0 {
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return []*model.Enrollment{}, err
+		}
+	}
+	return r.enrollments(ctx, courseID, db.Enrollment.StudentID.In(studentIDs))
+}
+
+func (r *mutationResolver) Unenroll(ctx context.Context, courseID string, studentID string) (*model.UnenrollResult, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return &model.UnenrollResult{}, err
+	}
+	results, err := r.Client.QuestionResult.FindMany(
+		db.QuestionResult.StudentID.Equals(studentID),
+		db.QuestionResult.Question.Where(
+			db.Question.Quiz.Where(
+				db.Quiz.CourseID.Equals(courseID),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.UnenrollResult{}, err
+	}
+	if len(results) > 0 {
+		return &model.UnenrollResult{}, errors.New("student has results in this course, withdraw them instead")
+	}
+	deleted, err := r.Client.Enrollment.FindMany(
+		db.Enrollment.CourseID.Equals(courseID),
+		db.Enrollment.StudentID.Equals(studentID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return &model.UnenrollResult{}, err
+	}
+	if deleted.Count == 0 {
+		return &model.UnenrollResult{}, errors.New("student isn't enrolled in this course")
+	}
+	return &model.UnenrollResult{
+		CourseID:  courseID,
+		StudentID: studentID,
+	}, nil
+}
+
+func (r *queryResolver) Enrollments(ctx context.Context, courseID string, includeWithdrawn *bool) ([]*model.Enrollment, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return []*model.Enrollment{}, err
+	}
+	where := []db.EnrollmentWhereParam{
+		db.Enrollment.Student.Where(
+			db.Student.User.Where(viewer.visibleUsers()...),
+		),
+	}
+	if includeWithdrawn == nil || !*includeWithdrawn {
+		where = activeEnrollment(where...)
+	}
+	return r.enrollments(ctx, courseID, where...)
+}
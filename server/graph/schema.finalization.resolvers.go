@@ -0,0 +1,100 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+func (r *courseResolver) FinalizedAt(ctx context.Context, obj *model.Course) (*time.Time, error) {
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(obj.ID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	finalizedAt, finalized := course.FinalizedAt()
+	if !finalized {
+		return nil, nil
+	}
+	return &finalizedAt, nil
+}
+
+func (r *courseResolver) LockHistory(ctx context.Context, obj *model.Course) ([]*model.CourseLockEvent, error) {
+	events, err := r.Client.CourseLockEvent.FindMany(
+		db.CourseLockEvent.CourseID.Equals(obj.ID),
+	).OrderBy(
+		db.CourseLockEvent.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.CourseLockEvent{}, err
+	}
+	history := []*model.CourseLockEvent{}
+	for _, event := range events {
+		reason, _ := event.Reason()
+		history = append(history, &model.CourseLockEvent{
+			Action:    model.CourseLockAction(event.Action),
+			ActorID:   event.ActorID,
+			Reason:    optionalString(reason),
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return history, nil
+}
+
+func (r *mutationResolver) FinalizeCourse(ctx context.Context, id string) (*model.Course, error) {
+	if err := r.requireUnlockedCourse(ctx, id, courseOwners); err != nil {
+		return &model.Course{}, err
+	}
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return &model.Course{}, err
+	}
+	if err := r.Client.Prisma.Transaction(r.finalizeCourses(viewer.ID, []string{id})...).Exec(ctx); err != nil {
+		return &model.Course{}, err
+	}
+	return (&queryResolver{r.Resolver}).Course(ctx, id)
+}
+
+func (r *mutationResolver) UnlockCourse(ctx context.Context, id string, reason string) (*model.Course, error) {
+	viewer, err := r.requireRole(ctx, roleProgramChair)
+	if err != nil {
+		return &model.Course{}, err
+	}
+	if strings.TrimSpace(reason) == "" {
+		return &model.Course{}, errors.New("a reason is required to unlock a course")
+	}
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Course{}, err
+	}
+	if _, finalized := course.FinalizedAt(); !finalized {
+		return &model.Course{}, errors.New("the course isn't finalized")
+	}
+	if err := r.Client.Prisma.Transaction(
+		r.Client.Course.FindUnique(
+			db.Course.ID.Equals(id),
+		).Update(
+			db.Course.FinalizedAt.SetOptional(nil),
+		).Tx(),
+		r.Client.CourseLockEvent.CreateOne(
+			db.CourseLockEvent.Course.Link(
+				db.Course.ID.Equals(id),
+			),
+			db.CourseLockEvent.Action.Set(db.CourseLockAction(model.CourseLockActionUnlocked)),
+			db.CourseLockEvent.ActorID.Set(viewer.ID),
+			db.CourseLockEvent.Reason.Set(reason),
+		).Tx(),
+	).Exec(ctx); err != nil {
+		return &model.Course{}, err
+	}
+	return (&queryResolver{r.Resolver}).Course(ctx, id)
+}
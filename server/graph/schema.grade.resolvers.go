@@ -0,0 +1,298 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+func (r *courseResolver) CategoryWeights(ctx context.Context, obj *model.Course) ([]*model.CategoryWeight, error) {
+	weights, err := r.Client.CategoryWeight.FindMany(
+		db.CategoryWeight.CourseID.Equals(obj.ID),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.CategoryWeight{}, err
+	}
+	categoryWeights := []*model.CategoryWeight{}
+	for _, weight := range weights {
+		categoryWeights = append(categoryWeights, &model.CategoryWeight{
+			Category: model.AssessmentCategory(weight.Category),
+			Weight:   weight.Weight,
+		})
+	}
+	return categoryWeights, nil
+}
+
+func (r *courseResolver) GradeScheme(ctx context.Context, obj *model.Course) (*model.GradeScheme, error) {
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(obj.ID),
+	).With(
+		db.Course.GradeScheme.Fetch().With(
+			db.GradeScheme.Boundaries.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scheme, ok := course.GradeScheme()
+	if !ok {
+		return nil, nil
+	}
+	return gradeSchemeModel(*scheme), nil
+}
+
+func (r *mutationResolver) SetCategoryWeights(ctx context.Context, courseID string, weights []*model.CategoryWeightInput) ([]*model.CategoryWeight, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return []*model.CategoryWeight{}, err
+	}
+	errs := inputErrors{}
+	seen := map[model.AssessmentCategory]bool{}
+	for i, weight := range weights {
+		path := fmt.Sprintf("weights[%d]", i)
+		if seen[weight.Category] {
+			errs.add(path+".category", "%s is weighted twice", weight.Category)
+		}
+		seen[weight.Category] = true
+		validateGradeWeight(path+".weight", &weight.Weight, &errs)
+	}
+	if err := errs.err(); err != nil {
+		return []*model.CategoryWeight{}, err
+	}
+	transactions := []transaction.Param{
+		r.Client.CategoryWeight.FindMany(
+			db.CategoryWeight.CourseID.Equals(courseID),
+		).Delete().Tx(),
+	}
+	categoryWeights := []*model.CategoryWeight{}
+	for _, weight := range weights {
+		transactions = append(transactions, r.Client.CategoryWeight.CreateOne(
+			db.CategoryWeight.Course.Link(
+				db.Course.ID.Equals(courseID),
+			),
+			db.CategoryWeight.Category.Set(db.AssessmentCategory(weight.Category)),
+			db.CategoryWeight.Weight.Set(weight.Weight),
+		).Tx())
+		categoryWeights = append(categoryWeights, &model.CategoryWeight{
+			Category: weight.Category,
+			Weight:   weight.Weight,
+		})
+	}
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return []*model.CategoryWeight{}, err
+	}
+	return categoryWeights, nil
+}
+
+func (r *mutationResolver) CreateGradeScheme(ctx context.Context, programID string, input model.GradeSchemeInput) (*model.GradeScheme, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.GradeScheme{}, err
+	}
+	if err := validateGradeSchemeInput(input); err != nil {
+		return &model.GradeScheme{}, err
+	}
+	id := uuid.New().String()
+	transactions := r.gradeSchemeTx(id, programID, nil, input, false)
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return &model.GradeScheme{}, err
+	}
+	return r.gradeScheme(ctx, id)
+}
+
+func (r *mutationResolver) EditGradeScheme(ctx context.Context, id string, input model.GradeSchemeInput) (*model.GradeScheme, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.GradeScheme{}, err
+	}
+	if err := validateGradeSchemeInput(input); err != nil {
+		return &model.GradeScheme{}, err
+	}
+	scheme, err := r.Client.GradeScheme.FindUnique(
+		db.GradeScheme.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.GradeScheme{}, err
+	}
+	if _, ok := scheme.CourseID(); ok {
+		return &model.GradeScheme{}, errors.New("a course's own grade scheme is changed with setCourseGradeScheme")
+	}
+	finalized, err := r.Client.Course.FindFirst(
+		db.Course.GradeSchemeID.Equals(id),
+		db.Course.Not(db.Course.FinalizedAt.IsNull()),
+	).Exec(ctx)
+	if err == nil {
+		return &model.GradeScheme{}, finalizedError(finalized.ID)
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return &model.GradeScheme{}, err
+	}
+	transactions := r.gradeSchemeTx(id, scheme.ProgramID, nil, input, true)
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return &model.GradeScheme{}, err
+	}
+	return r.gradeScheme(ctx, id)
+}
+
+func (r *mutationResolver) SetCourseGradeScheme(ctx context.Context, courseID string, gradeSchemeID *string, input *model.GradeSchemeInput) (*model.Course, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseOwners); err != nil {
+		return &model.Course{}, err
+	}
+	if gradeSchemeID != nil && input != nil {
+		return &model.Course{}, errors.New("pass either gradeSchemeID or input, not both")
+	}
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Course{}, err
+	}
+	transactions := []transaction.Param{}
+	link := db.Course.GradeScheme.Unlink()
+	switch {
+	case gradeSchemeID != nil:
+		scheme, err := r.Client.GradeScheme.FindUnique(
+			db.GradeScheme.ID.Equals(*gradeSchemeID),
+		).Exec(ctx)
+		if err != nil {
+			return &model.Course{}, err
+		}
+		if scheme.ProgramID != course.ProgramID {
+			return &model.Course{}, errors.New("grade scheme belongs to another program")
+		}
+		if schemeCourseID, ok := scheme.CourseID(); ok && schemeCourseID != courseID {
+			return &model.Course{}, errors.New("grade scheme belongs to another course")
+		}
+		link = db.Course.GradeScheme.Link(
+			db.GradeScheme.ID.Equals(scheme.ID),
+		)
+	case input != nil:
+		if err := validateGradeSchemeInput(*input); err != nil {
+			return &model.Course{}, err
+		}
+		own, err := r.Client.GradeScheme.FindFirst(
+			db.GradeScheme.CourseID.Equals(courseID),
+		).Exec(ctx)
+		if err != nil && !errors.Is(err, db.ErrNotFound) {
+			return &model.Course{}, err
+		}
+		id := uuid.New().String()
+		if own != nil {
+			id = own.ID
+		}
+		transactions = r.gradeSchemeTx(id, course.ProgramID, &courseID, *input, own != nil)
+		link = db.Course.GradeScheme.Link(
+			db.GradeScheme.ID.Equals(id),
+		)
+	}
+	transactions = append(transactions, r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).Update(
+		link,
+	).Tx())
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return &model.Course{}, err
+	}
+	return (&queryResolver{r.Resolver}).Course(ctx, courseID)
+}
+
+func (r *queryResolver) CourseGrade(ctx context.Context, courseID string, studentID string) (*model.CourseGrade, error) {
+	if err := r.requireGradeViewer(ctx, courseID, studentID); err != nil {
+		return &model.CourseGrade{}, err
+	}
+	grades, err := r.gradeBook(ctx, courseID, db.QuestionResult.StudentID.Equals(studentID))
+	if err != nil {
+		return &model.CourseGrade{}, err
+	}
+	return grades.grade(courseID, studentID), nil
+}
+
+func (r *queryResolver) CourseGrades(ctx context.Context, courseID string) (*model.CourseGrades, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseGraders); err != nil {
+		return &model.CourseGrades{}, err
+	}
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).With(
+		db.Course.GradeScheme.Fetch().With(
+			db.GradeScheme.Boundaries.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CourseGrades{}, err
+	}
+	grades := &model.CourseGrades{
+		CourseID:     courseID,
+		Students:     []*model.StudentLetterGrade{},
+		Distribution: []*model.GradeCount{},
+	}
+	scheme := defaultGradeScheme()
+	if own, ok := course.GradeScheme(); ok {
+		scheme = gradeSchemeModel(*own)
+		grades.Scheme = scheme
+	}
+	grades.Boundaries = scheme.Boundaries
+	book, err := r.gradeBook(ctx, courseID, enrolledResult(courseID, nil))
+	if err != nil {
+		return &model.CourseGrades{}, err
+	}
+	enrollments, err := r.Client.Enrollment.FindMany(
+		db.Enrollment.CourseID.Equals(courseID),
+		db.Enrollment.Status.Equals(db.EnrollmentStatus(model.EnrollmentStatusEnrolled)),
+	).With(
+		db.Enrollment.Student.Fetch().With(
+			db.Student.User.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.CourseGrades{}, err
+	}
+	counts := map[string]int{}
+	for _, enrollment := range enrollments {
+		user := enrollment.Student().User()
+		percentage := book.grade(courseID, enrollment.StudentID).Percentage
+		points, letter := letterGrade(scheme, percentage)
+		counts[letter] += 1
+		grades.Students = append(grades.Students, &model.StudentLetterGrade{
+			Student: &model.User{
+				ID:      user.ID,
+				Email:   user.Email,
+				Name:    user.Name,
+				Surname: user.Surname,
+			},
+			Percentage: percentage,
+			Points:     points,
+			Letter:     letter,
+		})
+	}
+	for _, boundary := range scheme.Boundaries {
+		grades.Distribution = append(grades.Distribution, &model.GradeCount{
+			Letter: boundary.Letter,
+			Count:  counts[boundary.Letter],
+		})
+	}
+	return grades, nil
+}
+
+func (r *queryResolver) GradeSchemes(ctx context.Context, programID string) ([]*model.GradeScheme, error) {
+	allSchemes, err := r.Client.GradeScheme.FindMany(
+		db.GradeScheme.ProgramID.Equals(programID),
+		db.GradeScheme.CourseID.IsNull(),
+	).With(
+		db.GradeScheme.Boundaries.Fetch(),
+	).OrderBy(
+		db.GradeScheme.Name.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.GradeScheme{}, err
+	}
+	schemes := []*model.GradeScheme{}
+	for _, scheme := range allSchemes {
+		schemes = append(schemes, gradeSchemeModel(scheme))
+	}
+	return schemes, nil
+}
@@ -0,0 +1,62 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+)
+
+func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error) {
+	typename, keys, err := decodeNodeID(id)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var node model.Node
+	switch {
+	case typename == "Program" && len(keys) == 1:
+		node, err = r.Program(ctx, keys[0])
+	case typename == "PLOGroup" && len(keys) == 1:
+		node, err = r.ploGroupNode(ctx, keys[0])
+	case typename == "PLO" && len(keys) == 1:
+		node, err = r.ploNode(ctx, keys[0])
+	case typename == "Course" && len(keys) == 1:
+		node, err = r.Course(ctx, keys[0])
+	case typename == "LO" && len(keys) == 1:
+		node, err = r.loNode(ctx, keys[0])
+	case typename == "LOLevel" && len(keys) == 2:
+		node, err = r.loLevelNode(ctx, keys[0], keys[1])
+	case typename == "User" && len(keys) == 1:
+		node, err = r.userNode(ctx, keys[0])
+	case typename == "Teacher" && len(keys) == 1:
+		node, err = r.teacher(ctx, keys[0])
+	case typename == "Quiz" && len(keys) == 1:
+		node, err = r.quizNode(ctx, keys[0], viewer.visibleResults()...)
+	case typename == "Question" && len(keys) == 1:
+		node, err = r.questionNode(ctx, keys[0], viewer.visibleResults()...)
+	case typename == "QuestionResult" && len(keys) == 2:
+		node, err = r.questionResultNode(ctx, keys[0], keys[1])
+	case typename == "QuestionLink" && len(keys) == 3:
+		node, err = r.questionLinkNode(ctx, keys[0], keys[1], keys[2])
+	default:
+		return nil, errors.New("unknown node type")
+	}
+	if err == nil {
+		err = r.requireNodeVisible(ctx, viewer, typename, keys)
+	}
+	// a node the viewer may not read looks the same as one that doesn't
+	// exist, so IDs can't be probed
+	if errors.Is(err, db.ErrNotFound) || errors.Is(err, errPermissionDenied) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
@@ -5,104 +5,145 @@ package graph
 
 import (
 	"api/server/db"
+	"api/server/graph/generated"
 	"api/server/graph/model"
 	"context"
-	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/prisma/prisma-client-go/runtime/transaction"
 )
 
-func (r *mutationResolver) CreateProgram(ctx context.Context, input model.CreateProgramInput) (*model.Program, error) {
-	teacherID, ok := ctx.Value("user_id").(string)
-	if !ok || teacherID == "" {
-		return &model.Program{}, errors.New("user not found")
-	}
-	createdProgram, err := r.Client.Program.CreateOne(
-		db.Program.Name.Set(input.Name),
-		db.Program.Description.Set(input.Description),
-		db.Program.Teacher.Link(
-			db.Teacher.ID.Equals(teacherID),
-		),
-	).Exec(ctx)
+func (r *mutationResolver) CreateProgram(ctx context.Context, input model.CreateProgramInput, idempotencyKey *string) (*model.Program, error) {
+	viewer, err := r.requireRole(ctx, roleProgramChair)
 	if err != nil {
 		return &model.Program{}, err
 	}
-	return &model.Program{
-		ID:          createdProgram.ID,
-		Name:        createdProgram.Name,
-		Description: createdProgram.Description,
-		TeacherID:   teacherID,
-	}, nil
+	teacherID := viewer.ID
+	created := &model.Program{}
+	err = r.idempotent(ctx, idempotencyKey, "createProgram", []interface{}{input}, created, func() error {
+		createdProgram, err := r.Client.Program.CreateOne(
+			db.Program.Name.Set(input.Name),
+			db.Program.Description.Set(input.Description),
+			db.Program.Teacher.Link(
+				db.Teacher.ID.Equals(teacherID),
+			),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		*created = model.Program{
+			ID:          createdProgram.ID,
+			Name:        createdProgram.Name,
+			Description: createdProgram.Description,
+			TeacherID:   teacherID,
+			Version:     createdProgram.Version,
+		}
+		return nil
+	})
+	if err != nil {
+		return &model.Program{}, err
+	}
+	return created, nil
 }
 
-func (r *mutationResolver) EditProgram(ctx context.Context, id string, input model.CreateProgramInput) (*model.Program, error) {
-	updated, err := r.Client.Program.FindUnique(
-		db.Program.ID.Equals(id),
-	).Update(
-		db.Program.Name.Set(input.Name),
-		db.Program.Description.Set(input.Description),
-	).Exec(ctx)
+func (r *mutationResolver) EditProgram(ctx context.Context, id string, input model.CreateProgramInput, expectedVersion *int) (*model.Program, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.Program{}, err
+	}
+	err := updateVersioned(func() (*db.BatchResult, error) {
+		return r.Client.Program.FindMany(
+			append(livePrograms(), db.Program.ID.Equals(id), db.Program.Version.EqualsIfPresent(expectedVersion))...,
+		).Update(
+			db.Program.Name.Set(input.Name),
+			db.Program.Description.Set(input.Description),
+			db.Program.Version.Increment(1),
+		).Exec(ctx)
+	}, func() (interface{}, error) {
+		return (&queryResolver{r.Resolver}).Program(ctx, id)
+	})
 	if err != nil {
 		return &model.Program{}, err
 	}
-	teacherID, _ := updated.TeacherID()
-	return &model.Program{
-		ID:          updated.ID,
-		Name:        updated.Name,
-		Description: updated.Description,
-		TeacherID:   teacherID,
-	}, nil
+	return (&queryResolver{r.Resolver}).Program(ctx, id)
 }
 
-func (r *mutationResolver) CreatePLOGroup(ctx context.Context, programID string, name string, input []*model.CreatePLOsInput) (*model.PLOGroup, error) {
-	createPLOGroup, err := r.Client.PLOgroup.CreateOne(
-		db.PLOgroup.Name.Set(name),
-		db.PLOgroup.Program.Link(
-			db.Program.ID.Equals(programID),
-		),
-	).Exec(ctx)
-	if err != nil {
+func (r *mutationResolver) CreatePLOGroup(ctx context.Context, programID string, name string, input []*model.CreatePLOsInput, idempotencyKey *string) (*model.PLOGroup, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
 		return &model.PLOGroup{}, err
 	}
-	transactions := []transaction.Param{}
-	for _, plo := range input {
-		transactions = append(transactions, r.Client.PLO.CreateOne(
-			db.PLO.Title.Set(plo.Title),
-			db.PLO.Description.Set(plo.Description),
-			db.PLO.PloGroup.Link(
-				db.PLOgroup.ID.Equals(createPLOGroup.ID),
+	created := &model.PLOGroup{}
+	err := r.idempotent(ctx, idempotencyKey, "createPLOGroup", []interface{}{programID, name, input}, created, func() error {
+		id := uuid.New().String()
+		create := r.Client.PLOgroup.CreateOne(
+			db.PLOgroup.Name.Set(name),
+			db.PLOgroup.Program.Link(
+				db.Program.ID.Equals(programID),
 			),
-		).Tx())
-	}
-	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			db.PLOgroup.ID.Set(id),
+		).Tx()
+		transactions := []transaction.Param{create}
+		for _, plo := range input {
+			transactions = append(transactions, r.Client.PLO.CreateOne(
+				db.PLO.Title.Set(plo.Title),
+				db.PLO.Description.Set(plo.Description),
+				db.PLO.PloGroup.Link(
+					db.PLOgroup.ID.Equals(id),
+				),
+			).Tx())
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		*created = *ploGroupModel(*create.Result())
+		return nil
+	})
+	if err != nil {
 		return &model.PLOGroup{}, err
 	}
-	return &model.PLOGroup{
-		ID:   createPLOGroup.ID,
-		Name: createPLOGroup.Name,
-	}, nil
+	return created, nil
 }
 
-func (r *mutationResolver) AddPLOs(ctx context.Context, ploGroupID string, input []*model.CreatePLOInput) (*model.AddPLOsResult, error) {
-	transactions := []transaction.Param{}
-	for _, plo := range input {
-		transactions = append(transactions, r.Client.PLO.CreateOne(
-			db.PLO.Title.Set(plo.Title),
-			db.PLO.Description.Set(plo.Description),
-			db.PLO.PloGroup.Link(
-				db.PLOgroup.ID.Equals(ploGroupID),
-			),
-		).Tx())
+func (r *mutationResolver) AddPLOs(ctx context.Context, ploGroupID string, input []*model.CreatePLOInput, idempotencyKey *string) (*model.AddPLOsResult, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.AddPLOsResult{}, err
 	}
-	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+	if err := r.requireCurrentPLOGroup(ctx, ploGroupID); err != nil {
 		return &model.AddPLOsResult{}, err
 	}
-	return &model.AddPLOsResult{
-		ID: ploGroupID,
-	}, nil
+	created := &model.AddPLOsResult{}
+	err := r.idempotent(ctx, idempotencyKey, "addPLOs", []interface{}{ploGroupID, input}, created, func() error {
+		transactions := []transaction.Param{}
+		for _, plo := range input {
+			transactions = append(transactions, r.Client.PLO.CreateOne(
+				db.PLO.Title.Set(plo.Title),
+				db.PLO.Description.Set(plo.Description),
+				db.PLO.PloGroup.Link(
+					db.PLOgroup.ID.Equals(ploGroupID),
+				),
+			).Tx())
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		*created = model.AddPLOsResult{
+			ID: ploGroupID,
+		}
+		return nil
+	})
+	if err != nil {
+		return &model.AddPLOsResult{}, err
+	}
+	return created, nil
 }
 
 func (r *mutationResolver) EditPLOGroup(ctx context.Context, id string, name string) (*model.PLOGroup, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.PLOGroup{}, err
+	}
+	if err := r.requireCurrentPLOGroup(ctx, id); err != nil {
+		return &model.PLOGroup{}, err
+	}
 	updated, err := r.Client.PLOgroup.FindUnique(
 		db.PLOgroup.ID.Equals(id),
 	).Update(
@@ -111,62 +152,98 @@ func (r *mutationResolver) EditPLOGroup(ctx context.Context, id string, name str
 	if err != nil {
 		return &model.PLOGroup{}, err
 	}
-	return &model.PLOGroup{
-		ID:   updated.ID,
-		Name: updated.Name,
-	}, nil
+	return ploGroupModel(*updated), nil
 }
 
-func (r *mutationResolver) CreatePlo(ctx context.Context, ploGroupID string, input model.CreatePLOInput) (*model.Plo, error) {
-	createdPLO, err := r.Client.PLO.CreateOne(
-		db.PLO.Title.Set(input.Title),
-		db.PLO.Description.Set(input.Description),
-		db.PLO.PloGroup.Link(
-			db.PLOgroup.ID.Equals(ploGroupID),
-		),
-	).Exec(ctx)
+func (r *mutationResolver) CreatePlo(ctx context.Context, ploGroupID string, input model.CreatePLOInput, idempotencyKey *string) (*model.Plo, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.Plo{}, err
+	}
+	if err := r.requireCurrentPLOGroup(ctx, ploGroupID); err != nil {
+		return &model.Plo{}, err
+	}
+	created := &model.Plo{}
+	err := r.idempotent(ctx, idempotencyKey, "createPLO", []interface{}{ploGroupID, input}, created, func() error {
+		createdPLO, err := r.Client.PLO.CreateOne(
+			db.PLO.Title.Set(input.Title),
+			db.PLO.Description.Set(input.Description),
+			db.PLO.PloGroup.Link(
+				db.PLOgroup.ID.Equals(ploGroupID),
+			),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		*created = model.Plo{
+			ID:          createdPLO.ID,
+			Title:       createdPLO.Title,
+			Description: createdPLO.Description,
+			PloGroupID:  createdPLO.PloGroupID,
+			Version:     createdPLO.Version,
+		}
+		return nil
+	})
 	if err != nil {
 		return &model.Plo{}, err
 	}
-	return &model.Plo{
-		ID:          createdPLO.ID,
-		Title:       createdPLO.Title,
-		Description: createdPLO.Description,
-		PloGroupID:  createdPLO.PloGroupID,
-	}, nil
+	return created, nil
 }
 
-func (r *mutationResolver) EditPlo(ctx context.Context, id string, title string, description string) (*model.Plo, error) {
-	updated, err := r.Client.PLO.FindUnique(
-		db.PLO.ID.Equals(id),
-	).Update(
-		db.PLO.Title.Set(title),
-		db.PLO.Description.Set(description),
-	).Exec(ctx)
+func (r *mutationResolver) EditPlo(ctx context.Context, id string, title string, description string, expectedVersion *int) (*model.Plo, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.Plo{}, err
+	}
+	if err := r.requireCurrentPLO(ctx, id); err != nil {
+		return &model.Plo{}, err
+	}
+	if err := r.checkPLOUnlocked(ctx, id); err != nil {
+		return &model.Plo{}, err
+	}
+	err := updateVersioned(func() (*db.BatchResult, error) {
+		return r.Client.PLO.FindMany(
+			db.PLO.ID.Equals(id),
+			db.PLO.Version.EqualsIfPresent(expectedVersion),
+		).Update(
+			db.PLO.Title.Set(title),
+			db.PLO.Description.Set(description),
+			db.PLO.Version.Increment(1),
+		).Exec(ctx)
+	}, func() (interface{}, error) {
+		return (&queryResolver{r.Resolver}).ploNode(ctx, id)
+	})
 	if err != nil {
 		return &model.Plo{}, err
 	}
-	return &model.Plo{
-		ID:          updated.ID,
-		Title:       updated.Title,
-		Description: updated.Description,
-		PloGroupID:  updated.PloGroupID,
-	}, nil
+	return (&queryResolver{r.Resolver}).ploNode(ctx, id)
 }
 
 func (r *mutationResolver) DeletePLOGroup(ctx context.Context, id string) (*model.DeletePLOGroupResult, error) {
-	deleted, err := r.Client.PLOgroup.FindUnique(
-		db.PLOgroup.ID.Equals(id),
-	).Delete().Exec(ctx)
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.DeletePLOGroupResult{}, err
+	}
+	if err := r.checkPLOGroupTrashable(ctx, id); err != nil {
+		return &model.DeletePLOGroupResult{}, err
+	}
+	now := time.Now()
+	trashed, err := r.setDeletedAt(ctx, model.TrashTypePloGroup, id, &now)
 	if err != nil {
 		return &model.DeletePLOGroupResult{}, err
 	}
 	return &model.DeletePLOGroupResult{
-		ID: deleted.ID,
+		ID: trashed.ID,
 	}, nil
 }
 
 func (r *mutationResolver) DeletePlo(ctx context.Context, id string) (*model.DeletePLOResult, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.DeletePLOResult{}, err
+	}
+	if err := r.requireCurrentPLO(ctx, id); err != nil {
+		return &model.DeletePLOResult{}, err
+	}
+	if err := r.checkPLOUnlocked(ctx, id); err != nil {
+		return &model.DeletePLOResult{}, err
+	}
 	deleted, err := r.Client.PLO.FindUnique(
 		db.PLO.ID.Equals(id),
 	).Delete().Exec(ctx)
@@ -178,8 +255,20 @@ func (r *mutationResolver) DeletePlo(ctx context.Context, id string) (*model.Del
 	}, nil
 }
 
+func (r *pLOResolver) ID(ctx context.Context, obj *model.Plo) (string, error) {
+	return encodeNodeID("PLO", obj.ID), nil
+}
+
+func (r *pLOGroupResolver) ID(ctx context.Context, obj *model.PLOGroup) (string, error) {
+	return encodeNodeID("PLOGroup", obj.ID), nil
+}
+
+func (r *programResolver) ID(ctx context.Context, obj *model.Program) (string, error) {
+	return encodeNodeID("Program", obj.ID), nil
+}
+
 func (r *queryResolver) Programs(ctx context.Context) ([]*model.Program, error) {
-	allPrograms, err := r.Client.Program.FindMany().Exec(ctx)
+	allPrograms, err := r.Client.Program.FindMany(livePrograms()...).Exec(ctx)
 	if err != nil {
 		return []*model.Program{}, err
 	}
@@ -191,14 +280,15 @@ func (r *queryResolver) Programs(ctx context.Context) ([]*model.Program, error)
 			Name:        program.Name,
 			Description: program.Description,
 			TeacherID:   teacherID,
+			Version:     program.Version,
 		})
 	}
 	return programs, nil
 }
 
 func (r *queryResolver) Program(ctx context.Context, programID string) (*model.Program, error) {
-	program, err := r.Client.Program.FindUnique(
-		db.Program.ID.Equals(programID),
+	program, err := r.Client.Program.FindFirst(
+		append(livePrograms(), db.Program.ID.Equals(programID))...,
 	).Exec(ctx)
 	if err != nil {
 		return &model.Program{}, err
@@ -209,22 +299,20 @@ func (r *queryResolver) Program(ctx context.Context, programID string) (*model.P
 		Name:        program.Name,
 		Description: program.Description,
 		TeacherID:   teacherID,
+		Version:     program.Version,
 	}, nil
 }
 
 func (r *queryResolver) PloGroups(ctx context.Context, programID string) ([]*model.PLOGroup, error) {
 	allPLOGroups, err := r.Client.PLOgroup.FindMany(
-		db.PLOgroup.ProgramID.Equals(programID),
+		append(livePLOGroups(), db.PLOgroup.ProgramID.Equals(programID))...,
 	).Exec(ctx)
 	if err != nil {
 		return []*model.PLOGroup{}, err
 	}
 	ploGroups := []*model.PLOGroup{}
 	for _, ploGroup := range allPLOGroups {
-		ploGroups = append(ploGroups, &model.PLOGroup{
-			ID:   ploGroup.ID,
-			Name: ploGroup.Name,
-		})
+		ploGroups = append(ploGroups, ploGroupModel(ploGroup))
 	}
 	return ploGroups, nil
 }
@@ -242,22 +330,21 @@ func (r *queryResolver) Plos(ctx context.Context, ploGroupID string) ([]*model.P
 			ID:          plo.ID,
 			Title:       plo.Title,
 			Description: plo.Description,
+			Version:     plo.Version,
 		})
 	}
 	return plos, nil
 }
 
-func (r *queryResolver) StudentsInProgram(ctx context.Context, programID string) ([]*model.User, error) {
+func (r *queryResolver) StudentsInProgram(ctx context.Context, programID string, termID *string) ([]*model.User, error) {
 	allStudents, err := r.Client.User.FindMany(
 		db.User.Student.Where(
-			db.Student.QuestionResults.Some(
-				db.QuestionResult.Question.Where(
-					db.Question.Quiz.Where(
-						db.Quiz.Course.Where(
-							db.Course.ProgramID.Equals(programID),
-						),
+			db.Student.Enrollments.Some(
+				activeEnrollment(
+					db.Enrollment.Course.Where(
+						append(liveCourses(), db.Course.ProgramID.Equals(programID), db.Course.TermID.EqualsIfPresent(termID))...,
 					),
-				),
+				)...,
 			),
 		),
 	).Exec(ctx)
@@ -307,3 +394,16 @@ func (r *queryResolver) Student(ctx context.Context, studentID string) (*model.U
 		Surname: student.User().Surname,
 	}, nil
 }
+
+// PLO returns generated.PLOResolver implementation.
+func (r *Resolver) PLO() generated.PLOResolver { return &pLOResolver{r} }
+
+// PLOGroup returns generated.PLOGroupResolver implementation.
+func (r *Resolver) PLOGroup() generated.PLOGroupResolver { return &pLOGroupResolver{r} }
+
+// Program returns generated.ProgramResolver implementation.
+func (r *Resolver) Program() generated.ProgramResolver { return &programResolver{r} }
+
+type pLOResolver struct{ *Resolver }
+type pLOGroupResolver struct{ *Resolver }
+type programResolver struct{ *Resolver }
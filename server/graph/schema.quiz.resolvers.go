@@ -5,53 +5,107 @@ package graph
 
 import (
 	"api/server/db"
+	"api/server/graph/generated"
 	"api/server/graph/model"
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+	"github.com/shopspring/decimal"
 )
 
-func (r *mutationResolver) CreateQuiz(ctx context.Context, courseID string, input *model.CreateQuizInput) (*model.CreateQuizResult, error) {
-	createdQuiz, err := r.Client.Quiz.CreateOne(
-		db.Quiz.Name.Set(input.Name),
-		db.Quiz.Course.Link(
-			db.Course.ID.Equals(courseID),
-		),
-		db.Quiz.CreatedAt.Set(input.CreatedAt),
-	).Exec(ctx)
-	if err != nil {
+func (r *mutationResolver) CreateQuiz(ctx context.Context, courseID string, input *model.CreateQuizInput, idempotencyKey *string) (*model.CreateQuizResult, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
 		return &model.CreateQuizResult{}, err
 	}
-	for _, questionInput := range input.Questions {
-		createdQuestion, err := r.Client.Question.CreateOne(
-			db.Question.Title.Set(questionInput.Title),
-			db.Question.MaxScore.Set(questionInput.MaxScore),
-			db.Question.Quiz.Link(
-				db.Quiz.ID.Equals(createdQuiz.ID),
-			),
-		).Exec(ctx)
+	created := &model.CreateQuizResult{}
+	err := r.idempotent(ctx, idempotencyKey, "createQuiz", []interface{}{courseID, input}, created, func() error {
+		if _, err := r.Client.Course.FindUnique(
+			db.Course.ID.Equals(courseID),
+		).Exec(ctx); err != nil {
+			return err
+		}
+		students, err := r.enrolledStudents(ctx, courseID, questionStudentIDs(input.Questions...))
 		if err != nil {
-			return &model.CreateQuizResult{}, err
+			return err
 		}
-		for _, resultInput := range questionInput.Results {
-			_, err := r.Client.QuestionResult.CreateOne(
-				db.QuestionResult.Question.Link(
-					db.Question.ID.Equals(createdQuestion.ID),
+		errs := inputErrors{}
+		validateGradeWeight("input.weight", input.Weight, &errs)
+		for i, questionInput := range input.Questions {
+			validateQuestionInput(fmt.Sprintf("input.questions[%d]", i), questionInput, students, &errs)
+		}
+		if err := errs.err(); err != nil {
+			return err
+		}
+		quizID := uuid.New().String()
+		params := []db.QuizSetParam{
+			db.Quiz.ID.Set(quizID),
+			db.Quiz.CreatedAt.Set(input.CreatedAt),
+			db.Quiz.Weight.SetIfPresent(input.Weight),
+		}
+		if input.Category != nil {
+			params = append(params, db.Quiz.Category.Set(db.AssessmentCategory(*input.Category)))
+		}
+		transactions := []transaction.Param{
+			r.Client.Quiz.CreateOne(
+				db.Quiz.Name.Set(input.Name),
+				db.Quiz.Course.Link(
+					db.Course.ID.Equals(courseID),
 				),
-				db.QuestionResult.Student.Link(
-					db.Student.ID.Equals(resultInput.StudentID),
+				params...,
+			).Tx(),
+		}
+		for _, questionInput := range input.Questions {
+			questionID := uuid.New().String()
+			transactions = append(transactions, r.Client.Question.CreateOne(
+				db.Question.Title.Set(questionInput.Title),
+				db.Question.MaxScore.Set(questionInput.MaxScore),
+				db.Question.Quiz.Link(
+					db.Quiz.ID.Equals(quizID),
 				),
-				db.QuestionResult.Score.Set(resultInput.Score),
-			).Exec(ctx)
-			if err != nil {
-				return &model.CreateQuizResult{}, err
+				db.Question.ID.Set(questionID),
+			).Tx())
+			for _, resultInput := range questionInput.Results {
+				transactions = append(transactions, r.Client.QuestionResult.CreateOne(
+					db.QuestionResult.Question.Link(
+						db.Question.ID.Equals(questionID),
+					),
+					db.QuestionResult.Student.Link(
+						db.Student.ID.Equals(resultInput.StudentID),
+					),
+					db.QuestionResult.Score.Set(resultInput.Score),
+				).Tx())
 			}
 		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		*created = model.CreateQuizResult{
+			ID: quizID,
+		}
+		return nil
+	})
+	if err != nil {
+		return &model.CreateQuizResult{}, err
 	}
-	return &model.CreateQuizResult{
-		ID: createdQuiz.ID,
-	}, nil
+	return created, nil
 }
 
 func (r *mutationResolver) CreateQuestionLink(ctx context.Context, input *model.CreateQuestionLinkInput) (*model.CreateQuestionLinkResult, error) {
+	if err := r.requireQuestionRole(ctx, input.QuestionID, courseEditors); err != nil {
+		return &model.CreateQuestionLinkResult{}, err
+	}
+	params := []db.QuestionLinkSetParam{}
+	if input.Weight != nil {
+		if err := validateLinkWeight(*input.Weight); err != nil {
+			return &model.CreateQuestionLinkResult{}, err
+		}
+		params = append(params, db.QuestionLink.Weight.Set(*input.Weight))
+	}
 	createdQuestionLink, err := r.Client.QuestionLink.CreateOne(
 		db.QuestionLink.Question.Link(
 			db.Question.ID.Equals(input.QuestionID),
@@ -62,6 +116,7 @@ func (r *mutationResolver) CreateQuestionLink(ctx context.Context, input *model.
 				db.LOlevel.Level.Equals(input.Level),
 			),
 		),
+		params...,
 	).Exec(ctx)
 	if err != nil {
 		return &model.CreateQuestionLinkResult{}, err
@@ -69,36 +124,70 @@ func (r *mutationResolver) CreateQuestionLink(ctx context.Context, input *model.
 	return &model.CreateQuestionLinkResult{
 		QuestionID: createdQuestionLink.QuestionID,
 		LoID:       createdQuestionLink.LoID,
+		Weight:     createdQuestionLink.Weight,
 	}, nil
 }
 
-func (r *mutationResolver) EditQuiz(ctx context.Context, id string, name string) (*model.EditQuizResult, error) {
+func (r *mutationResolver) EditQuiz(ctx context.Context, id string, name string, createdAt *time.Time, expectedVersion *int, category *model.AssessmentCategory, weight *float64) (*model.EditQuizResult, error) {
+	if err := r.requireQuizRole(ctx, id, courseEditors); err != nil {
+		return &model.EditQuizResult{}, err
+	}
+	errs := inputErrors{}
+	validateGradeWeight("weight", weight, &errs)
+	if err := errs.err(); err != nil {
+		return &model.EditQuizResult{}, err
+	}
+	params := []db.QuizSetParam{
+		db.Quiz.Name.Set(name),
+		db.Quiz.CreatedAt.SetIfPresent(createdAt),
+		db.Quiz.Weight.SetIfPresent(weight),
+		db.Quiz.Version.Increment(1),
+	}
+	if category != nil {
+		params = append(params, db.Quiz.Category.Set(db.AssessmentCategory(*category)))
+	}
+	err := updateVersioned(func() (*db.BatchResult, error) {
+		return r.Client.Quiz.FindMany(
+			append(liveQuizzes(), db.Quiz.ID.Equals(id), db.Quiz.Version.EqualsIfPresent(expectedVersion))...,
+		).Update(
+			params...,
+		).Exec(ctx)
+	}, func() (interface{}, error) {
+		return (&queryResolver{r.Resolver}).quizNode(ctx, id)
+	})
+	if err != nil {
+		return &model.EditQuizResult{}, err
+	}
 	updated, err := r.Client.Quiz.FindUnique(
 		db.Quiz.ID.Equals(id),
-	).Update(
-		db.Quiz.Name.Set(name),
 	).Exec(ctx)
 	if err != nil {
 		return &model.EditQuizResult{}, err
 	}
 	return &model.EditQuizResult{
-		ID: updated.ID,
+		ID:      updated.ID,
+		Version: updated.Version,
 	}, nil
 }
 
 func (r *mutationResolver) DeleteQuiz(ctx context.Context, id string) (*model.DeleteQuizResult, error) {
-	deleted, err := r.Client.Quiz.FindUnique(
-		db.Quiz.ID.Equals(id),
-	).Delete().Exec(ctx)
+	if err := r.requireQuizRole(ctx, id, courseEditors); err != nil {
+		return &model.DeleteQuizResult{}, err
+	}
+	now := time.Now()
+	trashed, err := r.setDeletedAt(ctx, model.TrashTypeQuiz, id, &now)
 	if err != nil {
 		return &model.DeleteQuizResult{}, err
 	}
 	return &model.DeleteQuizResult{
-		ID: deleted.ID,
+		ID: trashed.ID,
 	}, nil
 }
 
 func (r *mutationResolver) DeleteQuestionLink(ctx context.Context, input model.DeleteQuestionLinkInput) (*model.DeleteQuestionLinkResult, error) {
+	if err := r.requireQuestionRole(ctx, input.QuestionID, courseEditors); err != nil {
+		return &model.DeleteQuestionLinkResult{}, err
+	}
 	deleted, err := r.Client.QuestionLink.FindUnique(
 		db.QuestionLink.QuestionIDLoIDLevel(
 			db.QuestionLink.QuestionID.Equals(input.QuestionID),
@@ -115,18 +204,240 @@ func (r *mutationResolver) DeleteQuestionLink(ctx context.Context, input model.D
 	}, nil
 }
 
+func (r *mutationResolver) AddQuestion(ctx context.Context, quizID string, input model.CreateQuestionInput, idempotencyKey *string) (*model.AddQuestionResult, error) {
+	if err := r.requireQuizRole(ctx, quizID, courseEditors); err != nil {
+		return &model.AddQuestionResult{}, err
+	}
+	created := &model.AddQuestionResult{}
+	err := r.idempotent(ctx, idempotencyKey, "addQuestion", []interface{}{quizID, input}, created, func() error {
+		quiz, err := r.Client.Quiz.FindUnique(
+			db.Quiz.ID.Equals(quizID),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		students, err := r.enrolledStudents(ctx, quiz.CourseID, questionStudentIDs(&input))
+		if err != nil {
+			return err
+		}
+		errs := inputErrors{}
+		validateQuestionInput("input", &input, students, &errs)
+		if err := errs.err(); err != nil {
+			return err
+		}
+		questionID := uuid.New().String()
+		transactions := []transaction.Param{
+			r.Client.Question.CreateOne(
+				db.Question.Title.Set(input.Title),
+				db.Question.MaxScore.Set(input.MaxScore),
+				db.Question.Quiz.Link(
+					db.Quiz.ID.Equals(quizID),
+				),
+				db.Question.ID.Set(questionID),
+			).Tx(),
+		}
+		for _, resultInput := range input.Results {
+			transactions = append(transactions, r.Client.QuestionResult.CreateOne(
+				db.QuestionResult.Question.Link(
+					db.Question.ID.Equals(questionID),
+				),
+				db.QuestionResult.Student.Link(
+					db.Student.ID.Equals(resultInput.StudentID),
+				),
+				db.QuestionResult.Score.Set(resultInput.Score),
+			).Tx())
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			return err
+		}
+		*created = model.AddQuestionResult{
+			ID: questionID,
+		}
+		return nil
+	})
+	if err != nil {
+		return &model.AddQuestionResult{}, err
+	}
+	return created, nil
+}
+
+func (r *mutationResolver) EditQuestion(ctx context.Context, id string, input model.EditQuestionInput) (*model.EditQuestionResult, error) {
+	if err := r.requireQuestionRole(ctx, id, courseEditors); err != nil {
+		return &model.EditQuestionResult{}, err
+	}
+	if message := validateMaxScore(input.MaxScore); message != "" {
+		return &model.EditQuestionResult{}, errors.New(message)
+	}
+	// the question is locked first, so no result can be saved between the
+	// check and the new max score, see lockQuestions
+	update := r.Client.Question.FindMany(
+		db.Question.ID.Equals(id),
+		db.Question.Results.None(
+			db.QuestionResult.Score.Gt(input.MaxScore),
+		),
+	).Update(
+		db.Question.Title.Set(input.Title),
+		db.Question.MaxScore.Set(input.MaxScore),
+	).Tx()
+	if err := r.Client.Prisma.Transaction(r.lockQuestions(id), update).Exec(ctx); err != nil {
+		return &model.EditQuestionResult{}, err
+	}
+	if update.Result().Count == 0 {
+		highest, err := r.Client.QuestionResult.FindFirst(
+			db.QuestionResult.QuestionID.Equals(id),
+		).OrderBy(
+			db.QuestionResult.Score.Order(db.SortOrderDesc),
+		).Exec(ctx)
+		if err != nil {
+			return &model.EditQuestionResult{}, err
+		}
+		return &model.EditQuestionResult{}, fmt.Errorf("maxScore can't be lower than the existing score %s", highest.Score)
+	}
+	return &model.EditQuestionResult{
+		ID: id,
+	}, nil
+}
+
+func (r *mutationResolver) DeleteQuestion(ctx context.Context, id string) (*model.DeleteQuestionResult, error) {
+	if err := r.requireQuestionRole(ctx, id, courseEditors); err != nil {
+		return &model.DeleteQuestionResult{}, err
+	}
+	deleted, err := r.Client.Question.FindUnique(
+		db.Question.ID.Equals(id),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return &model.DeleteQuestionResult{}, err
+	}
+	return &model.DeleteQuestionResult{
+		ID: deleted.ID,
+	}, nil
+}
+
+func (r *mutationResolver) UpsertQuestionResults(ctx context.Context, quizID string, entries []*model.QuestionResultEntryInput) ([]*model.QuestionResultCell, error) {
+	if err := r.requireQuizRole(ctx, quizID, courseGraders); err != nil {
+		return []*model.QuestionResultCell{}, err
+	}
+	quiz, err := r.Client.Quiz.FindUnique(
+		db.Quiz.ID.Equals(quizID),
+	).With(
+		db.Quiz.Questions.Fetch().With(
+			db.Question.Results.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.QuestionResultCell{}, err
+	}
+	maxScores := map[string]decimal.Decimal{}
+	scores := map[string]map[string]decimal.Decimal{}
+	for _, question := range quiz.Questions() {
+		maxScores[question.ID] = question.MaxScore
+		scores[question.ID] = map[string]decimal.Decimal{}
+		for _, result := range question.Results() {
+			scores[question.ID][result.StudentID] = result.Score
+		}
+	}
+	studentIDs := []string{}
+	for _, entry := range entries {
+		studentIDs = append(studentIDs, entry.StudentID)
+	}
+	students, err := r.enrolledStudents(ctx, quiz.CourseID, studentIDs)
+	if err != nil {
+		return []*model.QuestionResultCell{}, err
+	}
+
+	cells := []*model.QuestionResultCell{}
+	transactions := []transaction.Param{}
+	seen := map[string]bool{}
+	locked := []string{}
+	invalid := false
+	for _, entry := range entries {
+		cell := &model.QuestionResultCell{
+			StudentID:  entry.StudentID,
+			QuestionID: entry.QuestionID,
+			Status:     model.QuestionResultStatusUnchanged,
+		}
+		cells = append(cells, cell)
+		message := ""
+		maxScore, inQuiz := maxScores[entry.QuestionID]
+		if !inQuiz {
+			message = "question is not in this quiz"
+		} else if entry.Score != nil && !students[entry.StudentID] {
+			message = "student isn't enrolled in the course"
+		} else if seen[entry.QuestionID+","+entry.StudentID] {
+			message = "duplicate entry"
+		} else if entry.Score != nil {
+			message = validateScore(*entry.Score, maxScore)
+		}
+		if message != "" {
+			cell.Status = model.QuestionResultStatusInvalid
+			cell.Message = &message
+			invalid = true
+			continue
+		}
+		seen[entry.QuestionID+","+entry.StudentID] = true
+		oldScore, exists := scores[entry.QuestionID][entry.StudentID]
+		switch {
+		case entry.Score == nil && exists:
+			cell.Status = model.QuestionResultStatusCleared
+			transactions = append(transactions, r.Client.QuestionResult.FindUnique(
+				db.QuestionResult.QuestionIDStudentID(
+					db.QuestionResult.QuestionID.Equals(entry.QuestionID),
+					db.QuestionResult.StudentID.Equals(entry.StudentID),
+				),
+			).Delete().Tx())
+		case entry.Score != nil && !exists:
+			cell.Status = model.QuestionResultStatusCreated
+			transactions = append(transactions, r.Client.QuestionResult.CreateOne(
+				db.QuestionResult.Question.Link(
+					db.Question.ID.Equals(entry.QuestionID),
+				),
+				db.QuestionResult.Student.Link(
+					db.Student.ID.Equals(entry.StudentID),
+				),
+				db.QuestionResult.Score.Set(*entry.Score),
+			).Tx())
+			transactions = append(transactions, r.checkedScore(entry.QuestionID, entry.StudentID, *entry.Score)...)
+			locked = append(locked, entry.QuestionID)
+		case entry.Score != nil && !entry.Score.Equal(oldScore):
+			cell.Status = model.QuestionResultStatusUpdated
+			transactions = append(transactions, r.checkedScore(entry.QuestionID, entry.StudentID, *entry.Score)...)
+			locked = append(locked, entry.QuestionID)
+		}
+	}
+	// a single bad cell rejects the whole grid, so the caller can fix it and resend
+	if invalid {
+		return rejectGrid(cells), nil
+	}
+	if len(locked) > 0 {
+		transactions = append([]transaction.Param{r.lockQuestions(locked...)}, transactions...)
+	}
+	if len(transactions) > 0 {
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			// a max score lowered since the grid was checked fails the
+			// transaction, so report the scores that no longer fit
+			if r.invalidateScoresAboveMax(ctx, quizID, entries, cells) {
+				return rejectGrid(cells), nil
+			}
+			return []*model.QuestionResultCell{}, err
+		}
+	}
+	return cells, nil
+}
+
 func (r *queryResolver) Quizzes(ctx context.Context, courseID string) ([]*model.Quiz, error) {
+	results, err := r.courseResults(ctx, courseID)
+	if err != nil {
+		return []*model.Quiz{}, err
+	}
 	quizzes := []*model.Quiz{}
 	allQuizzes, err := r.Client.Quiz.FindMany(
-		db.Quiz.Course.Where(
-			db.Course.ID.Equals(courseID),
-		),
+		liveQuizzes(db.Course.ID.Equals(courseID))...,
 	).With(
 		db.Quiz.Questions.Fetch().With(
 			db.Question.Links.Fetch().With(
 				db.QuestionLink.LoLevel.Fetch(),
 			),
-			db.Question.Results.Fetch(),
+			db.Question.Results.Fetch(results...),
 		),
 	).Exec(ctx)
 	if err != nil {
@@ -135,35 +446,52 @@ func (r *queryResolver) Quizzes(ctx context.Context, courseID string) ([]*model.
 	for _, quiz := range allQuizzes {
 		questions := []*model.Question{}
 		for _, question := range quiz.Questions() {
-			results := []*model.QuestionResult{}
-			loLinks := []*model.QuestionLink{}
-			for _, result := range question.Results() {
-				results = append(results, &model.QuestionResult{
-					StudentID: result.StudentID,
-					Score:     result.Score,
-				})
-			}
-			for _, loLink := range question.Links() {
-				loLinks = append(loLinks, &model.QuestionLink{
-					LoID:        loLink.LoID,
-					Level:       loLink.Level,
-					Description: loLink.LoLevel().Description,
-				})
-			}
-			questions = append(questions, &model.Question{
-				ID:       question.ID,
-				Title:    question.Title,
-				MaxScore: question.MaxScore,
-				Results:  results,
-				LoLinks:  loLinks,
-			})
+			questions = append(questions, questionModel(question))
 		}
 		quizzes = append(quizzes, &model.Quiz{
 			ID:        quiz.ID,
 			Name:      quiz.Name,
 			CreatedAt: quiz.CreatedAt,
 			Questions: questions,
+			Version:   quiz.Version,
+			Category:  model.AssessmentCategory(quiz.Category),
+			Weight:    quiz.Weight,
 		})
 	}
 	return quizzes, nil
 }
+
+func (r *questionResolver) ID(ctx context.Context, obj *model.Question) (string, error) {
+	return encodeNodeID("Question", obj.ID), nil
+}
+
+func (r *questionLinkResolver) ID(ctx context.Context, obj *model.QuestionLink) (string, error) {
+	return encodeNodeID("QuestionLink", obj.QuestionID, obj.LoID, strconv.Itoa(obj.Level)), nil
+}
+
+func (r *questionResultResolver) ID(ctx context.Context, obj *model.QuestionResult) (string, error) {
+	return encodeNodeID("QuestionResult", obj.QuestionID, obj.StudentID), nil
+}
+
+func (r *quizResolver) ID(ctx context.Context, obj *model.Quiz) (string, error) {
+	return encodeNodeID("Quiz", obj.ID), nil
+}
+
+// Question returns generated.QuestionResolver implementation.
+func (r *Resolver) Question() generated.QuestionResolver { return &questionResolver{r} }
+
+// QuestionLink returns generated.QuestionLinkResolver implementation.
+func (r *Resolver) QuestionLink() generated.QuestionLinkResolver { return &questionLinkResolver{r} }
+
+// QuestionResult returns generated.QuestionResultResolver implementation.
+func (r *Resolver) QuestionResult() generated.QuestionResultResolver {
+	return &questionResultResolver{r}
+}
+
+// Quiz returns generated.QuizResolver implementation.
+func (r *Resolver) Quiz() generated.QuizResolver { return &quizResolver{r} }
+
+type questionResolver struct{ *Resolver }
+type questionLinkResolver struct{ *Resolver }
+type questionResultResolver struct{ *Resolver }
+type quizResolver struct{ *Resolver }
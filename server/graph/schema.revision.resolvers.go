@@ -0,0 +1,213 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+func (r *mutationResolver) RevisePLOGroup(ctx context.Context, id string, name *string) (*model.PLOGroupRevision, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.PLOGroupRevision{}, err
+	}
+	ploGroup, err := r.Client.PLOgroup.FindFirst(
+		append(livePLOGroups(), db.PLOgroup.ID.Equals(id))...,
+	).With(
+		db.PLOgroup.Plos.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return &model.PLOGroupRevision{}, err
+	}
+	if _, superseded := ploGroup.SupersededAt(); superseded {
+		return &model.PLOGroupRevision{}, errors.New("only the latest revision of a PLO group can be revised")
+	}
+	newName := ploGroup.Name
+	if name != nil {
+		newName = *name
+	}
+	newID := uuid.New().String()
+	create := r.Client.PLOgroup.CreateOne(
+		db.PLOgroup.Name.Set(newName),
+		db.PLOgroup.Program.Link(
+			db.Program.ID.Equals(ploGroup.ProgramID),
+		),
+		db.PLOgroup.ID.Set(newID),
+		db.PLOgroup.Revision.Set(ploGroup.Revision+1),
+		db.PLOgroup.Previous.Link(
+			db.PLOgroup.ID.Equals(id),
+		),
+	).Tx()
+	transactions := []transaction.Param{
+		create,
+		r.Client.PLOgroup.FindUnique(
+			db.PLOgroup.ID.Equals(id),
+		).Update(
+			db.PLOgroup.SupersededAt.Set(time.Now()),
+		).Tx(),
+	}
+	mappings := []*model.PLOMapping{}
+	for _, plo := range ploGroup.Plos() {
+		ploID := uuid.New().String()
+		transactions = append(transactions,
+			r.Client.PLO.CreateOne(
+				db.PLO.Title.Set(plo.Title),
+				db.PLO.Description.Set(plo.Description),
+				db.PLO.PloGroup.Link(
+					db.PLOgroup.ID.Equals(newID),
+				),
+				db.PLO.ID.Set(ploID),
+			).Tx(),
+			r.Client.PLOmapping.CreateOne(
+				db.PLOmapping.OldPLO.Link(
+					db.PLO.ID.Equals(plo.ID),
+				),
+				db.PLOmapping.NewPLO.Link(
+					db.PLO.ID.Equals(ploID),
+				),
+			).Tx(),
+		)
+		mappings = append(mappings, &model.PLOMapping{
+			OldPloid: plo.ID,
+			NewPloid: ploID,
+		})
+	}
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return &model.PLOGroupRevision{}, err
+	}
+	return &model.PLOGroupRevision{
+		PloGroup: ploGroupModel(*create.Result()),
+		Mappings: mappings,
+	}, nil
+}
+
+func (r *mutationResolver) SetPLOMapping(ctx context.Context, oldPloid string, newPloids []string) ([]*model.PLOMapping, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	oldPLO, err := r.Client.PLO.FindUnique(
+		db.PLO.ID.Equals(oldPloid),
+	).With(
+		db.PLO.PloGroup.Fetch().With(
+			db.PLOgroup.Next.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	next, ok := oldPLO.PloGroup().Next()
+	if !ok {
+		return []*model.PLOMapping{}, errors.New("the PLO's group hasn't been revised yet")
+	}
+	newPLOs, err := r.Client.PLO.FindMany(
+		db.PLO.ID.In(newPloids),
+		db.PLO.PloGroupID.Equals(next.ID),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	found := map[string]bool{}
+	for _, plo := range newPLOs {
+		found[plo.ID] = true
+	}
+	errs := inputErrors{}
+	for i, newPLOID := range newPloids {
+		if !found[newPLOID] {
+			errs.add(fmt.Sprintf("newPLOIDs[%d]", i), "PLO %s isn't in the next revision", newPLOID)
+		}
+	}
+	if err := errs.err(); err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	transactions := []transaction.Param{
+		r.Client.PLOmapping.FindMany(
+			db.PLOmapping.OldPLOID.Equals(oldPloid),
+		).Delete().Tx(),
+	}
+	mappings := []*model.PLOMapping{}
+	for newPLOID := range found {
+		transactions = append(transactions, r.Client.PLOmapping.CreateOne(
+			db.PLOmapping.OldPLO.Link(
+				db.PLO.ID.Equals(oldPloid),
+			),
+			db.PLOmapping.NewPLO.Link(
+				db.PLO.ID.Equals(newPLOID),
+			),
+		).Tx())
+		mappings = append(mappings, &model.PLOMapping{
+			OldPloid: oldPloid,
+			NewPloid: newPLOID,
+		})
+	}
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	return mappings, nil
+}
+
+func (r *queryResolver) PloGroupRevisions(ctx context.Context, ploGroupID string) ([]*model.PLOGroup, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return []*model.PLOGroup{}, err
+	}
+	revisions, err := r.ploRevisions(ctx, ploGroupID)
+	if err != nil {
+		return []*model.PLOGroup{}, err
+	}
+	revisionIDs := []string{}
+	for _, ploGroup := range revisions {
+		revisionIDs = append(revisionIDs, ploGroup.ID)
+	}
+	visible, err := r.Client.PLOgroup.FindMany(
+		append(viewer.visiblePLOGroups(), db.PLOgroup.ID.In(revisionIDs))...,
+	).Exec(ctx)
+	if err != nil {
+		return []*model.PLOGroup{}, err
+	}
+	isVisible := map[string]bool{}
+	for _, ploGroup := range visible {
+		isVisible[ploGroup.ID] = true
+	}
+	ploGroups := []*model.PLOGroup{}
+	for _, ploGroup := range revisions {
+		if isVisible[ploGroup.ID] {
+			ploGroups = append(ploGroups, ploGroupModel(ploGroup))
+		}
+	}
+	return ploGroups, nil
+}
+
+func (r *queryResolver) PloMappings(ctx context.Context, ploGroupID string) ([]*model.PLOMapping, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	mapping := []db.PLOmappingWhereParam{
+		db.PLOmapping.OldPLO.Where(
+			viewer.visiblePLOs(append(livePLOGroups(), db.PLOgroup.ID.Equals(ploGroupID))...)...,
+		),
+		db.PLOmapping.NewPLO.Where(
+			viewer.visiblePLOs(livePLOGroups()...)...,
+		),
+	}
+	allMappings, err := r.Client.PLOmapping.FindMany(mapping...).Exec(ctx)
+	if err != nil {
+		return []*model.PLOMapping{}, err
+	}
+	mappings := []*model.PLOMapping{}
+	for _, mapping := range allMappings {
+		mappings = append(mappings, &model.PLOMapping{
+			OldPloid: mapping.OldPLOID,
+			NewPloid: mapping.NewPLOID,
+		})
+	}
+	return mappings, nil
+}
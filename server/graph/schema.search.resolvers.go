@@ -0,0 +1,47 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/graph/model"
+	"context"
+	"strings"
+)
+
+func (r *queryResolver) Search(ctx context.Context, term string, types []model.SearchType) ([]model.SearchResult, error) {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return []model.SearchResult{}, err
+	}
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return []model.SearchResult{}, nil
+	}
+	types = searchTypes(types)
+	results := []model.SearchResult{}
+	// The viewer's permissions are applied to each page of ranked hits, so
+	// pages are fetched until enough visible results are found.
+	for offset := 0; len(results) < searchLimit; offset += searchCandidates {
+		hits := []searchHit{}
+		if err := r.Client.Prisma.QueryRaw(searchQuery(types, offset), term, searchPattern(term)).Exec(ctx, &hits); err != nil {
+			return []model.SearchResult{}, err
+		}
+		found, err := r.visibleSearchHits(ctx, viewer, hits)
+		if err != nil {
+			return []model.SearchResult{}, err
+		}
+		for _, hit := range hits {
+			if result, ok := found[hit.Type][hit.ID]; ok {
+				results = append(results, result)
+			}
+			if len(results) == searchLimit {
+				break
+			}
+		}
+		if len(hits) < searchCandidates {
+			break
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,190 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/generated"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (r *courseResolver) Sections(ctx context.Context, obj *model.Course) ([]*model.Section, error) {
+	return r.sections(ctx, obj.ID)
+}
+
+func (r *mutationResolver) CreateSection(ctx context.Context, courseID string, name string) (*model.Section, error) {
+	if err := r.requireCourseRole(ctx, courseID, courseEditors); err != nil {
+		return &model.Section{}, err
+	}
+	section, err := r.Client.Section.CreateOne(
+		db.Section.Name.Set(name),
+		db.Section.Course.Link(
+			db.Course.ID.Equals(courseID),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Section{}, err
+	}
+	return sectionModel(*section), nil
+}
+
+func (r *mutationResolver) EditSection(ctx context.Context, id string, name string) (*model.Section, error) {
+	if _, err := r.requireSectionRole(ctx, id, courseEditors); err != nil {
+		return &model.Section{}, err
+	}
+	section, err := r.Client.Section.FindUnique(
+		db.Section.ID.Equals(id),
+	).Update(
+		db.Section.Name.Set(name),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Section{}, err
+	}
+	return sectionModel(*section), nil
+}
+
+func (r *mutationResolver) DeleteSection(ctx context.Context, id string) (*model.DeleteSectionResult, error) {
+	section, err := r.requireSectionRole(ctx, id, courseEditors)
+	if err != nil {
+		return &model.DeleteSectionResult{}, err
+	}
+	if err := r.checkUnlocked(ctx, section.CourseID); err != nil {
+		return &model.DeleteSectionResult{}, err
+	}
+	section, err = r.Client.Section.FindUnique(
+		db.Section.ID.Equals(id),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return &model.DeleteSectionResult{}, err
+	}
+	return &model.DeleteSectionResult{
+		ID: section.ID,
+	}, nil
+}
+
+func (r *mutationResolver) SetStudentSection(ctx context.Context, courseID string, studentIDs []string, sectionID *string) ([]*model.Enrollment, error) {
+	if err := r.requireUnlockedCourse(ctx, courseID, courseEditors); err != nil {
+		return []*model.Enrollment{}, err
+	}
+	if sectionID != nil {
+		if err := r.checkSection(ctx, courseID, *sectionID); err != nil {
+			return []*model.Enrollment{}, err
+		}
+	}
+	sections, err := r.studentSections(ctx, courseID)
+	if err != nil {
+		return []*model.Enrollment{}, err
+	}
+	errs := inputErrors{}
+	for i, studentID := range studentIDs {
+		if _, enrolled := sections[studentID]; !enrolled {
+			errs.add(fmt.Sprintf("studentIDs[%d]", i), "student %s isn't enrolled in this course", studentID)
+		}
+	}
+	if err := errs.err(); err != nil {
+		return []*model.Enrollment{}, err
+	}
+	if _, err := r.Client.Enrollment.FindMany(
+		db.Enrollment.CourseID.Equals(courseID),
+		db.Enrollment.StudentID.In(studentIDs),
+	).Update(
+		db.Enrollment.SectionID.SetOptional(sectionID),
+	).Exec(ctx); err != nil {
+		return []*model.Enrollment{}, err
+	}
+	return r.enrollments(ctx, courseID, db.Enrollment.StudentID.In(studentIDs))
+}
+
+func (r *mutationResolver) AddSectionStaff(ctx context.Context, sectionID string, teacherID string) (*model.Section, error) {
+	section, err := r.requireSectionRole(ctx, sectionID, courseEditors)
+	if err != nil {
+		return &model.Section{}, err
+	}
+	staff, err := r.courseStaff(ctx, section.CourseID)
+	if err != nil {
+		return &model.Section{}, err
+	}
+	if staffMember(staff, teacherID) == nil {
+		return &model.Section{}, errors.New("teacher isn't on the course staff")
+	}
+	if _, err := r.Client.SectionStaff.CreateOne(
+		db.SectionStaff.Section.Link(
+			db.Section.ID.Equals(sectionID),
+		),
+		db.SectionStaff.Teacher.Link(
+			db.Teacher.ID.Equals(teacherID),
+		),
+	).Exec(ctx); err != nil {
+		return &model.Section{}, err
+	}
+	return sectionModel(*section), nil
+}
+
+func (r *mutationResolver) RemoveSectionStaff(ctx context.Context, sectionID string, teacherID string) (*model.Section, error) {
+	section, err := r.requireSectionRole(ctx, sectionID, courseEditors)
+	if err != nil {
+		return &model.Section{}, err
+	}
+	deleted, err := r.Client.SectionStaff.FindMany(
+		db.SectionStaff.SectionID.Equals(sectionID),
+		db.SectionStaff.TeacherID.Equals(teacherID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return &model.Section{}, err
+	}
+	if deleted.Count == 0 {
+		return &model.Section{}, errors.New("teacher isn't on the section staff")
+	}
+	return sectionModel(*section), nil
+}
+
+func (r *queryResolver) Sections(ctx context.Context, courseID string) ([]*model.Section, error) {
+	return r.sections(ctx, courseID)
+}
+
+func (r *sectionResolver) Students(ctx context.Context, obj *model.Section) ([]*model.User, error) {
+	enrollments, err := r.enrollments(ctx, obj.CourseID, activeEnrollment(
+		db.Enrollment.SectionID.Equals(obj.ID),
+	)...)
+	if err != nil {
+		return []*model.User{}, err
+	}
+	students := []*model.User{}
+	for _, enrollment := range enrollments {
+		students = append(students, enrollment.Student)
+	}
+	return students, nil
+}
+
+func (r *sectionResolver) Staff(ctx context.Context, obj *model.Section) ([]*model.User, error) {
+	allStaff, err := r.Client.SectionStaff.FindMany(
+		db.SectionStaff.SectionID.Equals(obj.ID),
+	).With(
+		db.SectionStaff.Teacher.Fetch().With(
+			db.Teacher.User.Fetch(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.User{}, err
+	}
+	staff := []*model.User{}
+	for _, member := range allStaff {
+		user := member.Teacher().User()
+		staff = append(staff, &model.User{
+			ID:      user.ID,
+			Email:   user.Email,
+			Name:    user.Name,
+			Surname: user.Surname,
+		})
+	}
+	return staff, nil
+}
+
+// Section returns generated.SectionResolver implementation.
+func (r *Resolver) Section() generated.SectionResolver { return &sectionResolver{r} }
+
+type sectionResolver struct{ *Resolver }
@@ -0,0 +1,179 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/generated"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (r *mutationResolver) CreateTeacher(ctx context.Context, input model.CreateTeacherInput) (*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleDeveloper); err != nil {
+		return &model.Teacher{}, err
+	}
+	if err := validateRole(input.Role); err != nil {
+		return &model.Teacher{}, err
+	}
+	if _, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(input.ID),
+	).Exec(ctx); err == nil {
+		return &model.Teacher{}, fmt.Errorf("teacher %s already exists", input.ID)
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return &model.Teacher{}, err
+	}
+	if err := r.Client.Prisma.Transaction(
+		r.Client.User.UpsertOne(
+			db.User.ID.Equals(input.ID),
+		).Create(
+			db.User.ID.Set(input.ID),
+			db.User.Email.Set(input.Email),
+			db.User.Name.Set(input.Name),
+			db.User.Surname.Set(input.Surname),
+		).Update(
+			db.User.Email.Set(input.Email),
+			db.User.Name.Set(input.Name),
+			db.User.Surname.Set(input.Surname),
+		).Tx(),
+		r.Client.Teacher.CreateOne(
+			db.Teacher.User.Link(
+				db.User.ID.Equals(input.ID),
+			),
+			db.Teacher.Role.Set(input.Role),
+		).Tx(),
+	).Exec(ctx); err != nil {
+		return &model.Teacher{}, err
+	}
+	return r.teacher(ctx, input.ID)
+}
+
+func (r *mutationResolver) EditTeacher(ctx context.Context, id string, input model.EditTeacherInput) (*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleDeveloper); err != nil {
+		return &model.Teacher{}, err
+	}
+	if _, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).Exec(ctx); err != nil {
+		return &model.Teacher{}, err
+	}
+	if _, err := r.Client.User.FindUnique(
+		db.User.ID.Equals(id),
+	).Update(
+		db.User.Email.Set(input.Email),
+		db.User.Name.Set(input.Name),
+		db.User.Surname.Set(input.Surname),
+	).Exec(ctx); err != nil {
+		return &model.Teacher{}, err
+	}
+	return r.teacher(ctx, id)
+}
+
+func (r *mutationResolver) SetTeacherRole(ctx context.Context, id string, role int) (*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleDeveloper); err != nil {
+		return &model.Teacher{}, err
+	}
+	if err := validateRole(role); err != nil {
+		return &model.Teacher{}, err
+	}
+	teacher, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Teacher{}, err
+	}
+	if teacher.Role == roleDeveloper && teacher.Active && role != roleDeveloper {
+		if err := r.keepDeveloper(ctx, id); err != nil {
+			return &model.Teacher{}, err
+		}
+	}
+	if _, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).Update(
+		db.Teacher.Role.Set(role),
+	).Exec(ctx); err != nil {
+		return &model.Teacher{}, err
+	}
+	return r.teacher(ctx, id)
+}
+
+func (r *mutationResolver) DeactivateTeacher(ctx context.Context, id string) (*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleDeveloper); err != nil {
+		return &model.Teacher{}, err
+	}
+	teacher, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Teacher{}, err
+	}
+	if teacher.Role == roleDeveloper && teacher.Active {
+		if err := r.keepDeveloper(ctx, id); err != nil {
+			return &model.Teacher{}, err
+		}
+	}
+	if _, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).Update(
+		db.Teacher.Active.Set(false),
+	).Exec(ctx); err != nil {
+		return &model.Teacher{}, err
+	}
+	return r.teacher(ctx, id)
+}
+
+func (r *mutationResolver) ReactivateTeacher(ctx context.Context, id string) (*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleDeveloper); err != nil {
+		return &model.Teacher{}, err
+	}
+	if _, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).Update(
+		db.Teacher.Active.Set(true),
+	).Exec(ctx); err != nil {
+		return &model.Teacher{}, err
+	}
+	return r.teacher(ctx, id)
+}
+
+func (r *queryResolver) Teachers(ctx context.Context, includeInactive *bool) ([]*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return []*model.Teacher{}, err
+	}
+	where := []db.TeacherWhereParam{}
+	if includeInactive == nil || !*includeInactive {
+		where = append(where, db.Teacher.Active.Equals(true))
+	}
+	allTeachers, err := r.Client.Teacher.FindMany(where...).With(
+		db.Teacher.User.Fetch(),
+		db.Teacher.Programs.Fetch(livePrograms()...),
+		db.Teacher.Courses.Fetch(liveCourses()...),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.Teacher{}, err
+	}
+	teachers := []*model.Teacher{}
+	for _, teacher := range allTeachers {
+		teachers = append(teachers, teacherModel(teacher))
+	}
+	return teachers, nil
+}
+
+func (r *queryResolver) Teacher(ctx context.Context, id string) (*model.Teacher, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.Teacher{}, err
+	}
+	return r.teacher(ctx, id)
+}
+
+func (r *teacherResolver) ID(ctx context.Context, obj *model.Teacher) (string, error) {
+	return encodeNodeID("Teacher", obj.ID), nil
+}
+
+// Teacher returns generated.TeacherResolver implementation.
+func (r *Resolver) Teacher() generated.TeacherResolver { return &teacherResolver{r} }
+
+type teacherResolver struct{ *Resolver }
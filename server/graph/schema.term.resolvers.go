@@ -0,0 +1,169 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+)
+
+func (r *mutationResolver) CreateTerm(ctx context.Context, input model.CreateTermInput) (*model.Term, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.Term{}, err
+	}
+	if err := validateTermInput(input); err != nil {
+		return &model.Term{}, err
+	}
+	termID := uuid.New().String()
+	if err := r.Client.Prisma.Transaction(
+		r.Client.Term.CreateOne(
+			db.Term.Code.Set(input.Code),
+			db.Term.Semester.Set(input.Semester),
+			db.Term.Year.Set(input.Year),
+			db.Term.StartDate.Set(input.StartDate),
+			db.Term.EndDate.Set(input.EndDate),
+			db.Term.ID.Set(termID),
+		).Tx(),
+		r.Client.Course.FindMany(
+			db.Course.TermID.IsNull(),
+			db.Course.Semester.Equals(input.Semester),
+			db.Course.Year.Equals(input.Year),
+		).Update(
+			db.Course.TermID.Set(termID),
+		).Tx(),
+	).Exec(ctx); err != nil {
+		return &model.Term{}, err
+	}
+	return r.term(ctx, termID)
+}
+
+func (r *mutationResolver) EditTerm(ctx context.Context, id string, input model.CreateTermInput) (*model.Term, error) {
+	if _, err := r.requireRole(ctx, roleProgramChair); err != nil {
+		return &model.Term{}, err
+	}
+	if err := validateTermInput(input); err != nil {
+		return &model.Term{}, err
+	}
+	if err := r.checkTermUnlocked(ctx, id, input); err != nil {
+		return &model.Term{}, err
+	}
+	if err := r.Client.Prisma.Transaction(
+		r.Client.Term.FindUnique(
+			db.Term.ID.Equals(id),
+		).Update(
+			db.Term.Code.Set(input.Code),
+			db.Term.Semester.Set(input.Semester),
+			db.Term.Year.Set(input.Year),
+			db.Term.StartDate.Set(input.StartDate),
+			db.Term.EndDate.Set(input.EndDate),
+		).Tx(),
+		r.Client.Course.FindMany(
+			db.Course.TermID.Equals(id),
+		).Update(
+			db.Course.Semester.Set(input.Semester),
+			db.Course.Year.Set(input.Year),
+		).Tx(),
+	).Exec(ctx); err != nil {
+		return &model.Term{}, err
+	}
+	return r.term(ctx, id)
+}
+
+func (r *mutationResolver) SetTermStatus(ctx context.Context, id string, status model.TermStatus) (*model.Term, error) {
+	viewer, err := r.requireRole(ctx, roleProgramChair)
+	if err != nil {
+		return &model.Term{}, err
+	}
+	if status == model.TermStatusActive {
+		active, err := r.Client.Term.FindFirst(
+			db.Term.Status.Equals(db.TermStatus(model.TermStatusActive)),
+			db.Term.Not(db.Term.ID.Equals(id)),
+		).Exec(ctx)
+		if err == nil {
+			return &model.Term{}, fmt.Errorf("term %s is already active, close it first", active.Code)
+		} else if !errors.Is(err, db.ErrNotFound) {
+			return &model.Term{}, err
+		}
+	}
+	transactions := []transaction.Param{
+		r.Client.Term.FindUnique(
+			db.Term.ID.Equals(id),
+		).Update(
+			db.Term.Status.Set(db.TermStatus(status)),
+		).Tx(),
+	}
+	// closing a term finalizes its courses; reopening it leaves them finalized
+	if status == model.TermStatusClosed {
+		courses, err := r.Client.Course.FindMany(
+			db.Course.TermID.Equals(id),
+			db.Course.FinalizedAt.IsNull(),
+		).Exec(ctx)
+		if err != nil {
+			return &model.Term{}, err
+		}
+		courseIDs := []string{}
+		for _, course := range courses {
+			courseIDs = append(courseIDs, course.ID)
+		}
+		transactions = append(transactions, r.finalizeCourses(viewer.ID, courseIDs)...)
+	}
+	if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+		return &model.Term{}, err
+	}
+	return r.term(ctx, id)
+}
+
+func (r *queryResolver) Terms(ctx context.Context, status *model.TermStatus) ([]*model.Term, error) {
+	var statuses []db.TermStatus
+	if status != nil {
+		statuses = []db.TermStatus{db.TermStatus(*status)}
+	}
+	allTerms, err := r.Client.Term.FindMany(
+		db.Term.Status.InIfPresent(statuses),
+	).OrderBy(
+		db.Term.StartDate.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.Term{}, err
+	}
+	terms := []*model.Term{}
+	for _, term := range allTerms {
+		terms = append(terms, termModel(term))
+	}
+	return terms, nil
+}
+
+func (r *queryResolver) Term(ctx context.Context, id string) (*model.Term, error) {
+	return r.term(ctx, id)
+}
+
+func (r *queryResolver) CurrentTerm(ctx context.Context) (*model.Term, error) {
+	// an active term wins, otherwise fall back to the dates of open terms
+	term, err := r.Client.Term.FindFirst(
+		db.Term.Status.Equals(db.TermStatus(model.TermStatusActive)),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		now := time.Now()
+		term, err = r.Client.Term.FindFirst(
+			db.Term.Not(db.Term.Status.Equals(db.TermStatus(model.TermStatusClosed))),
+			db.Term.StartDate.Lte(now),
+			db.Term.EndDate.Gt(now),
+		).OrderBy(
+			db.Term.StartDate.Order(db.SortOrderDesc),
+		).Exec(ctx)
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return termModel(*term), nil
+}
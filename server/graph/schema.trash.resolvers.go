@@ -0,0 +1,103 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+func (r *mutationResolver) Trash(ctx context.Context, typeArg model.TrashType, id string) (*model.TrashItem, error) {
+	if err := r.requireTrashRole(ctx, typeArg, id); err != nil {
+		return &model.TrashItem{}, err
+	}
+	if typeArg == model.TrashTypePloGroup {
+		if err := r.checkPLOGroupTrashable(ctx, id); err != nil {
+			return &model.TrashItem{}, err
+		}
+	}
+	now := time.Now()
+	return r.setDeletedAt(ctx, typeArg, id, &now)
+}
+
+func (r *mutationResolver) Restore(ctx context.Context, typeArg model.TrashType, id string) (*model.TrashItem, error) {
+	if err := r.requireTrashRole(ctx, typeArg, id); err != nil {
+		return &model.TrashItem{}, err
+	}
+	return r.setDeletedAt(ctx, typeArg, id, nil)
+}
+
+func (r *queryResolver) Trashed(ctx context.Context, typeArg *model.TrashType) ([]*model.TrashItem, error) {
+	if _, err := r.requireRole(ctx, roleTeacher); err != nil {
+		return []*model.TrashItem{}, err
+	}
+	items := []*model.TrashItem{}
+	if typeArg == nil || *typeArg == model.TrashTypeProgram {
+		programs, err := r.Client.Program.FindMany(
+			db.Program.Not(db.Program.DeletedAt.IsNull()),
+		).Exec(ctx)
+		if err != nil {
+			return []*model.TrashItem{}, err
+		}
+		for _, program := range programs {
+			deletedAt, trashed := program.DeletedAt()
+			items = append(items, r.trashItem(model.TrashTypeProgram, program.ID, program.Name, deletedAt, trashed))
+		}
+	}
+	if typeArg == nil || *typeArg == model.TrashTypePloGroup {
+		ploGroups, err := r.Client.PLOgroup.FindMany(
+			db.PLOgroup.Not(db.PLOgroup.DeletedAt.IsNull()),
+		).Exec(ctx)
+		if err != nil {
+			return []*model.TrashItem{}, err
+		}
+		for _, ploGroup := range ploGroups {
+			deletedAt, trashed := ploGroup.DeletedAt()
+			items = append(items, r.trashItem(model.TrashTypePloGroup, ploGroup.ID, ploGroup.Name, deletedAt, trashed))
+		}
+	}
+	if typeArg == nil || *typeArg == model.TrashTypeCourse {
+		courses, err := r.Client.Course.FindMany(
+			db.Course.Not(db.Course.DeletedAt.IsNull()),
+		).Exec(ctx)
+		if err != nil {
+			return []*model.TrashItem{}, err
+		}
+		for _, course := range courses {
+			deletedAt, trashed := course.DeletedAt()
+			items = append(items, r.trashItem(model.TrashTypeCourse, course.ID, course.Name, deletedAt, trashed))
+		}
+	}
+	if typeArg == nil || *typeArg == model.TrashTypeQuiz {
+		quizzes, err := r.Client.Quiz.FindMany(
+			db.Quiz.Not(db.Quiz.DeletedAt.IsNull()),
+		).Exec(ctx)
+		if err != nil {
+			return []*model.TrashItem{}, err
+		}
+		for _, quiz := range quizzes {
+			deletedAt, trashed := quiz.DeletedAt()
+			items = append(items, r.trashItem(model.TrashTypeQuiz, quiz.ID, quiz.Name, deletedAt, trashed))
+		}
+	}
+	// only list what the viewer could restore
+	restorable := []*model.TrashItem{}
+	for _, item := range items {
+		err := r.requireTrashRole(ctx, item.Type, item.ID)
+		if err == nil {
+			restorable = append(restorable, item)
+		} else if !errors.Is(err, errPermissionDenied) && !isFinalizedError(err) {
+			return []*model.TrashItem{}, err
+		}
+	}
+	items = restorable
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(*items[j].DeletedAt)
+	})
+	return items, nil
+}
@@ -7,34 +7,77 @@ import (
 	"api/server/db"
 	"api/server/graph/model"
 	"context"
+	"fmt"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
 )
 
-func (r *mutationResolver) CreateStudents(ctx context.Context, input []*model.CreateStudentInput) ([]*model.CreateStudentResult, error) {
-	createdStudents := []*model.CreateStudentResult{}
-	for _, student := range input {
-		created, err := r.Client.User.UpsertOne(
-			db.User.ID.Equals(student.ID),
-		).Create(
-			db.User.ID.Set(student.ID),
-			db.User.Email.Set(student.Email),
-			db.User.Name.Set(student.Name),
-			db.User.Surname.Set(student.Surname),
-		).Update(
-			db.User.Email.Set(student.Email),
-			db.User.Name.Set(student.Name),
-			db.User.Surname.Set(student.Surname),
-		).Exec(ctx)
+func (r *mutationResolver) CreateStudents(ctx context.Context, input []*model.CreateStudentInput, idempotencyKey *string) ([]*model.CreateStudentResult, error) {
+	if _, err := r.requireRole(ctx, roleTeacher); err != nil {
+		return []*model.CreateStudentResult{}, err
+	}
+	created := []*model.CreateStudentResult{}
+	err := r.idempotent(ctx, idempotencyKey, "createStudents", []interface{}{input}, &created, func() error {
+		errs := inputErrors{}
+		studentIDs := []string{}
+		seen := map[string]bool{}
+		for i, student := range input {
+			path := fmt.Sprintf("input[%d]", i)
+			if student.ID == "" {
+				errs.add(path, "id can't be empty")
+			} else if seen[student.ID] {
+				errs.add(path, "student %s is listed more than once", student.ID)
+			}
+			if student.Email == "" {
+				errs.add(path, "email can't be empty")
+			}
+			seen[student.ID] = true
+			studentIDs = append(studentIDs, student.ID)
+		}
+		if err := errs.err(); err != nil {
+			return err
+		}
+		existing, err := r.existingStudents(ctx, studentIDs)
 		if err != nil {
-			return []*model.CreateStudentResult{}, err
+			return err
+		}
+		createdStudents := []*model.CreateStudentResult{}
+		transactions := []transaction.Param{}
+		for _, student := range input {
+			transactions = append(transactions, r.Client.User.UpsertOne(
+				db.User.ID.Equals(student.ID),
+			).Create(
+				db.User.ID.Set(student.ID),
+				db.User.Email.Set(student.Email),
+				db.User.Name.Set(student.Name),
+				db.User.Surname.Set(student.Surname),
+			).Update(
+				db.User.Email.Set(student.Email),
+				db.User.Name.Set(student.Name),
+				db.User.Surname.Set(student.Surname),
+			).Tx())
+			if !existing[student.ID] {
+				transactions = append(transactions, r.Client.Student.CreateOne(
+					db.Student.User.Link(
+						db.User.ID.Equals(student.ID),
+					),
+				).Tx())
+			}
+			createdStudents = append(createdStudents, &model.CreateStudentResult{
+				ID: student.ID,
+			})
+		}
+		if err := r.Client.Prisma.Transaction(transactions...).Exec(ctx); err != nil {
+			if isUniqueConstraintError(err) {
+				err = r.studentConflicts(ctx, input, existing, err)
+			}
+			return err
 		}
-		r.Client.Student.CreateOne(
-			db.Student.User.Link(
-				db.User.ID.Equals(created.ID),
-			),
-		).Exec(ctx)
-		createdStudents = append(createdStudents, &model.CreateStudentResult{
-			ID: created.ID,
-		})
+		created = createdStudents
+		return nil
+	})
+	if err != nil {
+		return []*model.CreateStudentResult{}, err
 	}
-	return createdStudents, nil
+	return created, nil
 }
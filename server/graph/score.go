@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"fmt"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+	"github.com/shopspring/decimal"
+)
+
+// Scores are decimals so half marks add up exactly. They're kept to
+// scorePlaces decimal places, and only turned into floats once a percentage
+// has been worked out.
+
+const scorePlaces = 2
+
+// scorePercentage returns the part of maxScore that score is.
+func scorePercentage(score decimal.Decimal, maxScore decimal.Decimal) float64 {
+	if maxScore.Sign() <= 0 {
+		return 0
+	}
+	return score.Div(maxScore).InexactFloat64()
+}
+
+func validateMaxScore(maxScore decimal.Decimal) string {
+	if maxScore.Sign() <= 0 {
+		return "maxScore must be greater than 0"
+	}
+	if !maxScore.Equal(maxScore.Round(scorePlaces)) {
+		return fmt.Sprintf("maxScore can't have more than %d decimal places", scorePlaces)
+	}
+	return ""
+}
+
+// validateScore returns why a score can't be given on a question worth
+// maxScore, or "" if it can.
+func validateScore(score decimal.Decimal, maxScore decimal.Decimal) string {
+	if score.Sign() < 0 || score.GreaterThan(maxScore) {
+		return fmt.Sprintf("score must be between 0 and %s", maxScore)
+	}
+	if !score.Equal(score.Round(scorePlaces)) {
+		return fmt.Sprintf("score can't have more than %d decimal places", scorePlaces)
+	}
+	return ""
+}
+
+// A result must never be above its question's max score, even when
+// editQuestion and upsertQuestionResults run at the same time. Both start
+// their transaction with lockQuestions, so one waits for the other, and
+// upsertQuestionResults writes every score with checkedScore against the max
+// score as it is once the lock is held.
+
+// lockQuestions returns a write that changes nothing but locks the questions'
+// rows until the transaction it is part of ends.
+func (r *Resolver) lockQuestions(questionIDs ...string) transaction.Param {
+	return r.Client.Question.FindMany(
+		db.Question.ID.In(questionIDs),
+	).Update(
+		db.Question.MaxScore.Increment(decimal.Zero),
+	).Tx()
+}
+
+// checkedScore returns the writes that set an existing result's score if it
+// still fits the question's max score. A score above it is deleted first, so
+// the update fails and rolls back the whole transaction.
+func (r *Resolver) checkedScore(questionID string, studentID string, score decimal.Decimal) []transaction.Param {
+	return []transaction.Param{
+		r.Client.QuestionResult.FindMany(
+			db.QuestionResult.QuestionID.Equals(questionID),
+			db.QuestionResult.StudentID.Equals(studentID),
+			db.QuestionResult.Question.Where(
+				db.Question.MaxScore.Lt(score),
+			),
+		).Delete().Tx(),
+		r.Client.QuestionResult.FindUnique(
+			db.QuestionResult.QuestionIDStudentID(
+				db.QuestionResult.QuestionID.Equals(questionID),
+				db.QuestionResult.StudentID.Equals(studentID),
+			),
+		).Update(
+			db.QuestionResult.Score.Set(score),
+		).Tx(),
+	}
+}
+
+// invalidateScoresAboveMax marks the cells of a score grid whose score no
+// longer fits its question's max score, and reports whether there were any.
+// The cells are in the order of the entries they were made from.
+func (r *Resolver) invalidateScoresAboveMax(ctx context.Context, quizID string, entries []*model.QuestionResultEntryInput, cells []*model.QuestionResultCell) bool {
+	questions, err := r.Client.Question.FindMany(
+		db.Question.QuizID.Equals(quizID),
+	).Exec(ctx)
+	if err != nil {
+		return false
+	}
+	maxScores := map[string]decimal.Decimal{}
+	for _, question := range questions {
+		maxScores[question.ID] = question.MaxScore
+	}
+	invalid := false
+	for i, cell := range cells {
+		if entries[i].Score == nil || cell.Status == model.QuestionResultStatusInvalid {
+			continue
+		}
+		if message := validateScore(*entries[i].Score, maxScores[cell.QuestionID]); message != "" {
+			cell.Status = model.QuestionResultStatusInvalid
+			cell.Message = &message
+			invalid = true
+		}
+	}
+	return invalid
+}
+
+// rejectGrid marks a score grid as not saved. Only the invalid cells keep
+// their status, so the caller can fix them and resend the grid.
+func rejectGrid(cells []*model.QuestionResultCell) []*model.QuestionResultCell {
+	for _, cell := range cells {
+		if cell.Status != model.QuestionResultStatusInvalid {
+			cell.Status = model.QuestionResultStatusUnchanged
+		}
+	}
+	return cells
+}
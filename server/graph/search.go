@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// searchCandidates is how many ranked rows are fetched at a time before
+	// the viewer's permissions are applied; searchLimit caps what is returned.
+	searchCandidates = 200
+	searchLimit      = 50
+)
+
+// searchSources holds the table and the searchable text of every type that
+// search can return. Only these constants are ever spliced into the query;
+// the term itself is always passed as a parameter.
+var searchSources = map[model.SearchType]struct {
+	table    string
+	document string
+}{
+	model.SearchTypeProgram: {`"Program"`, `"name" || ' ' || "description"`},
+	model.SearchTypeCourse:  {`"Course"`, `"name" || ' ' || "description"`},
+	model.SearchTypePlo:     {`"PLO"`, `"title" || ' ' || "description"`},
+	model.SearchTypeLo:      {`"LO"`, `"title"`},
+	model.SearchTypeUser:    {`"User"`, `"id" || ' ' || "name" || ' ' || "surname" || ' ' || "email"`},
+}
+
+type searchHit struct {
+	Type model.SearchType `json:"type"`
+	ID   string           `json:"id"`
+	Rank float64          `json:"rank"`
+}
+
+// searchQuery ranks full-text matches with ts_rank and lets plain substring
+// matches through as well, so partial words and student IDs are still found.
+// $1 is the term and $2 the ILIKE pattern built by searchPattern. It returns
+// one page of searchCandidates hits, in an order that is stable across pages.
+func searchQuery(types []model.SearchType, offset int) string {
+	selects := []string{}
+	for _, searchType := range types {
+		source, ok := searchSources[searchType]
+		if !ok {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf(
+			`SELECT '%[1]s' AS "type", "id", (ts_rank(to_tsvector('simple', %[2]s), plainto_tsquery('simple', $1)) + CASE WHEN %[2]s ILIKE $2 THEN 0.1 ELSE 0 END)::float8 AS "rank" FROM %[3]s WHERE to_tsvector('simple', %[2]s) @@ plainto_tsquery('simple', $1) OR %[2]s ILIKE $2`,
+			searchType, source.document, source.table,
+		))
+	}
+	return fmt.Sprintf(`%s ORDER BY "rank" DESC, "type", "id" LIMIT %d OFFSET %d`, strings.Join(selects, " UNION ALL "), searchCandidates, offset)
+}
+
+// searchTypes returns each of the requested types once, or every type when
+// none are given.
+func searchTypes(types []model.SearchType) []model.SearchType {
+	if len(types) == 0 {
+		return model.AllSearchType
+	}
+	unique := []model.SearchType{}
+	seen := map[model.SearchType]bool{}
+	for _, searchType := range types {
+		if !seen[searchType] {
+			seen[searchType] = true
+			unique = append(unique, searchType)
+		}
+	}
+	return unique
+}
+
+func searchPattern(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	return "%" + escaped + "%"
+}
+
+// visibleSearchHits loads the records behind a page of hits, keyed by type
+// and ID, leaving out what the viewer may not read.
+func (r *Resolver) visibleSearchHits(ctx context.Context, viewer *viewer, hits []searchHit) (map[model.SearchType]map[string]model.SearchResult, error) {
+	ids := map[model.SearchType][]string{}
+	for _, hit := range hits {
+		ids[hit.Type] = append(ids[hit.Type], hit.ID)
+	}
+	found := map[model.SearchType]map[string]model.SearchResult{}
+	for _, searchType := range model.AllSearchType {
+		found[searchType] = map[string]model.SearchResult{}
+	}
+	if len(ids[model.SearchTypeProgram]) > 0 {
+		programs, err := r.Client.Program.FindMany(
+			append(append(viewer.visiblePrograms(), livePrograms()...), db.Program.ID.In(ids[model.SearchTypeProgram]))...,
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, program := range programs {
+			teacherID, _ := program.TeacherID()
+			found[model.SearchTypeProgram][program.ID] = &model.Program{
+				ID:          program.ID,
+				Name:        program.Name,
+				Description: program.Description,
+				TeacherID:   teacherID,
+				Version:     program.Version,
+			}
+		}
+	}
+	if len(ids[model.SearchTypeCourse]) > 0 {
+		courses, err := r.Client.Course.FindMany(
+			append(append(viewer.visibleCourses(), liveCourses()...), db.Course.ID.In(ids[model.SearchTypeCourse]))...,
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, course := range courses {
+			ploGroupID, _ := course.PloGroupID()
+			teacherID, _ := course.TeacherID()
+			found[model.SearchTypeCourse][course.ID] = &model.Course{
+				ID:          course.ID,
+				Name:        course.Name,
+				Description: course.Description,
+				Semester:    course.Semester,
+				Year:        course.Year,
+				PloGroupID:  ploGroupID,
+				ProgramID:   course.ProgramID,
+				TeacherID:   teacherID,
+				Version:     course.Version,
+			}
+		}
+	}
+	if len(ids[model.SearchTypePlo]) > 0 {
+		plos, err := r.Client.PLO.FindMany(
+			append(viewer.visiblePLOs(livePLOGroups()...), db.PLO.ID.In(ids[model.SearchTypePlo]))...,
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, plo := range plos {
+			found[model.SearchTypePlo][plo.ID] = &model.Plo{
+				ID:          plo.ID,
+				Title:       plo.Title,
+				Description: plo.Description,
+				PloGroupID:  plo.PloGroupID,
+				Version:     plo.Version,
+			}
+		}
+	}
+	if len(ids[model.SearchTypeLo]) > 0 {
+		los, err := r.Client.LO.FindMany(
+			append(viewer.visibleLOs(liveCourses()...), db.LO.ID.In(ids[model.SearchTypeLo]))...,
+		).With(
+			db.LO.Levels.Fetch(),
+			db.LO.Links.Fetch().With(
+				db.LOlink.Plo.Fetch(),
+			),
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, lo := range los {
+			levels := []*model.LOLevel{}
+			for _, level := range lo.Levels() {
+				levels = append(levels, &model.LOLevel{
+					LoID:        level.LoID,
+					Level:       level.Level,
+					Description: level.Description,
+				})
+			}
+			ploLinks := []*model.LOPLOLink{}
+			for _, link := range lo.Links() {
+				ploLinks = append(ploLinks, loLinkModel(link))
+			}
+			found[model.SearchTypeLo][lo.ID] = &model.Lo{
+				ID:       lo.ID,
+				Title:    lo.Title,
+				Levels:   levels,
+				PloLinks: ploLinks,
+				Version:  lo.Version,
+			}
+		}
+	}
+	if len(ids[model.SearchTypeUser]) > 0 {
+		users, err := r.Client.User.FindMany(
+			append(viewer.visibleUsers(), db.User.ID.In(ids[model.SearchTypeUser]))...,
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			found[model.SearchTypeUser][user.ID] = &model.User{
+				ID:      user.ID,
+				Email:   user.Email,
+				Name:    user.Name,
+				Surname: user.Surname,
+			}
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+)
+
+// A section splits a course's students between teachers. Each enrollment is
+// in at most one section, while a staff member can take several. Sections
+// only group people: quizzes, LOs and results stay with the course.
+
+func (r *Resolver) requireSectionRole(ctx context.Context, sectionID string, roles []model.CourseStaffRole) (*db.SectionModel, error) {
+	section, err := r.Client.Section.FindUnique(
+		db.Section.ID.Equals(sectionID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.requireCourseRole(ctx, section.CourseID, roles); err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+func (r *Resolver) sections(ctx context.Context, courseID string) ([]*model.Section, error) {
+	allSections, err := r.Client.Section.FindMany(
+		db.Section.CourseID.Equals(courseID),
+	).OrderBy(
+		db.Section.Name.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.Section{}, err
+	}
+	sections := []*model.Section{}
+	for _, section := range allSections {
+		sections = append(sections, sectionModel(section))
+	}
+	return sections, nil
+}
+
+func sectionModel(section db.SectionModel) *model.Section {
+	return &model.Section{
+		ID:       section.ID,
+		CourseID: section.CourseID,
+		Name:     section.Name,
+	}
+}
+
+// studentSections maps the students enrolled in a course to their section,
+// or to nil for students who aren't in one.
+func (r *Resolver) studentSections(ctx context.Context, courseID string) (map[string]*string, error) {
+	enrollments, err := r.Client.Enrollment.FindMany(
+		db.Enrollment.CourseID.Equals(courseID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sections := map[string]*string{}
+	for _, enrollment := range enrollments {
+		if sectionID, ok := enrollment.SectionID(); ok {
+			sections[enrollment.StudentID] = &sectionID
+		} else {
+			sections[enrollment.StudentID] = nil
+		}
+	}
+	return sections, nil
+}
+
+// checkSection makes sure a section belongs to the given course.
+func (r *Resolver) checkSection(ctx context.Context, courseID string, sectionID string) error {
+	section, err := r.Client.Section.FindUnique(
+		db.Section.ID.Equals(sectionID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if section.CourseID != courseID {
+		return errors.New("section isn't in this course")
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+)
+
+// Staff roles allowed to do each kind of change in a course. Program chairs
+// and developers may do anything. A course's teacherID counts as an owner
+// even without a staff record, which is how courses created before staff
+// existed keep working.
+var (
+	courseOwners  = []model.CourseStaffRole{model.CourseStaffRoleOwner}
+	courseEditors = []model.CourseStaffRole{model.CourseStaffRoleOwner, model.CourseStaffRoleCoInstructor}
+	courseGraders = []model.CourseStaffRole{model.CourseStaffRoleOwner, model.CourseStaffRoleCoInstructor, model.CourseStaffRoleTa}
+)
+
+func (r *Resolver) courseStaff(ctx context.Context, courseID string) ([]*model.CourseStaff, error) {
+	course, err := r.Client.Course.FindUnique(
+		db.Course.ID.Equals(courseID),
+	).With(
+		db.Course.Teacher.Fetch().With(
+			db.Teacher.User.Fetch(),
+		),
+		db.Course.Staff.Fetch().With(
+			db.CourseStaff.Teacher.Fetch().With(
+				db.Teacher.User.Fetch(),
+			),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return []*model.CourseStaff{}, err
+	}
+	staff := []*model.CourseStaff{}
+	listed := false
+	teacherID, hasTeacher := course.TeacherID()
+	for _, member := range course.Staff() {
+		if member.TeacherID == teacherID {
+			listed = true
+		}
+		staff = append(staff, &model.CourseStaff{
+			CourseID:  course.ID,
+			TeacherID: member.TeacherID,
+			Teacher: &model.User{
+				ID:      member.TeacherID,
+				Email:   member.Teacher().User().Email,
+				Name:    member.Teacher().User().Name,
+				Surname: member.Teacher().User().Surname,
+			},
+			Role: model.CourseStaffRole(member.Role),
+		})
+	}
+	if teacher, ok := course.Teacher(); hasTeacher && ok && !listed {
+		staff = append([]*model.CourseStaff{{
+			CourseID:  course.ID,
+			TeacherID: teacher.ID,
+			Teacher: &model.User{
+				ID:      teacher.ID,
+				Email:   teacher.User().Email,
+				Name:    teacher.User().Name,
+				Surname: teacher.User().Surname,
+			},
+			Role: model.CourseStaffRoleOwner,
+		}}, staff...)
+	}
+	return staff, nil
+}
+
+// requireCourseRole checks that the viewer has one of the roles in a course
+// that isn't in the trash.
+func (r *Resolver) requireCourseRole(ctx context.Context, courseID string, roles []model.CourseStaffRole) error {
+	return r.requireStaffRole(ctx, courseID, roles, liveCourses()...)
+}
+
+// requireStaffRole is requireCourseRole for a course that matches the given
+// filters, so restoring a course can check the roles of a trashed one.
+func (r *Resolver) requireStaffRole(ctx context.Context, courseID string, roles []model.CourseStaffRole, course ...db.CourseWhereParam) error {
+	viewer, err := r.getViewer(ctx)
+	if err != nil {
+		return err
+	}
+	if !viewer.IsTeacher {
+		return errPermissionDenied
+	}
+	if _, err := r.Client.Course.FindFirst(
+		append(course, db.Course.ID.Equals(courseID))...,
+	).Exec(ctx); err != nil {
+		return err
+	}
+	if viewer.Role >= roleProgramChair {
+		return nil
+	}
+	staff, err := r.courseStaff(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	for _, member := range staff {
+		if member.TeacherID != viewer.ID {
+			continue
+		}
+		for _, role := range roles {
+			if member.Role == role {
+				return nil
+			}
+		}
+	}
+	return errPermissionDenied
+}
+
+// requireLORole, requireQuizRole and requireQuestionRole guard changes to a
+// course's outcomes, quizzes and scores, so they also reject finalized
+// courses.
+
+func (r *Resolver) requireLORole(ctx context.Context, loID string, roles []model.CourseStaffRole) error {
+	lo, err := r.Client.LO.FindUnique(
+		db.LO.ID.Equals(loID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return r.requireUnlockedCourse(ctx, lo.CourseID, roles)
+}
+
+func (r *Resolver) requireQuizRole(ctx context.Context, quizID string, roles []model.CourseStaffRole) error {
+	quiz, err := r.Client.Quiz.FindFirst(
+		append(liveQuizzes(), db.Quiz.ID.Equals(quizID))...,
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return r.requireUnlockedCourse(ctx, quiz.CourseID, roles)
+}
+
+func (r *Resolver) requireQuestionRole(ctx context.Context, questionID string, roles []model.CourseStaffRole) error {
+	question, err := r.Client.Question.FindFirst(
+		db.Question.ID.Equals(questionID),
+		db.Question.Quiz.Where(liveQuizzes()...),
+	).With(
+		db.Question.Quiz.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	return r.requireUnlockedCourse(ctx, question.Quiz().CourseID, roles)
+}
+
+// staffMember returns a member of the course staff, or nil if the teacher
+// isn't on it.
+func staffMember(staff []*model.CourseStaff, teacherID string) *model.CourseStaff {
+	for _, member := range staff {
+		if member.TeacherID == teacherID {
+			return member
+		}
+	}
+	return nil
+}
+
+// otherOwner returns an owner of the course other than the given teacher.
+func otherOwner(staff []*model.CourseStaff, teacherID string) *model.CourseStaff {
+	for _, member := range staff {
+		if member.TeacherID != teacherID && member.Role == model.CourseStaffRoleOwner {
+			return member
+		}
+	}
+	return nil
+}
+
+// requireTrashRole checks that the viewer may move an item to the trash or
+// restore it. Programs and PLO groups don't belong to a course staff, so
+// only program chairs may trash them.
+func (r *Resolver) requireTrashRole(ctx context.Context, trashType model.TrashType, id string) error {
+	switch trashType {
+	case model.TrashTypeCourse:
+		if err := r.requireStaffRole(ctx, id, courseOwners); err != nil {
+			return err
+		}
+		return r.checkUnlocked(ctx, id)
+	case model.TrashTypeQuiz:
+		quiz, err := r.Client.Quiz.FindUnique(
+			db.Quiz.ID.Equals(id),
+		).Exec(ctx)
+		if err != nil {
+			return err
+		}
+		return r.requireUnlockedCourse(ctx, quiz.CourseID, courseEditors)
+	}
+	_, err := r.requireRole(ctx, roleProgramChair)
+	return err
+}
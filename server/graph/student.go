@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"fmt"
+)
+
+// existingStudents returns which of the given IDs belong to a student.
+func (r *Resolver) existingStudents(ctx context.Context, studentIDs []string) (map[string]bool, error) {
+	students, err := r.Client.Student.FindMany(
+		db.Student.ID.In(studentIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, student := range students {
+		existing[student.ID] = true
+	}
+	return existing, nil
+}
+
+// studentConflicts turns a unique constraint failure of createStudents into
+// row errors. Which students existed was checked before the transaction, so
+// the rows to report are the students another request created in between.
+// The original error is returned when no row can be blamed.
+func (r *Resolver) studentConflicts(ctx context.Context, input []*model.CreateStudentInput, existing map[string]bool, err error) error {
+	studentIDs := []string{}
+	for _, student := range input {
+		studentIDs = append(studentIDs, student.ID)
+	}
+	current, findErr := r.existingStudents(ctx, studentIDs)
+	if findErr != nil {
+		return err
+	}
+	errs := inputErrors{}
+	for i, student := range input {
+		if current[student.ID] && !existing[student.ID] {
+			errs.add(fmt.Sprintf("input[%d]", i), "student %s was created by another request", student.ID)
+		}
+	}
+	if len(errs) == 0 {
+		return err
+	}
+	return errs.err()
+}
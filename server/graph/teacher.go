@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+	"fmt"
+)
+
+func validateRole(role int) error {
+	if role < roleTeacher || role > roleDeveloper {
+		return fmt.Errorf("role must be between %d and %d", roleTeacher, roleDeveloper)
+	}
+	return nil
+}
+
+func (r *Resolver) teacher(ctx context.Context, id string) (*model.Teacher, error) {
+	teacher, err := r.Client.Teacher.FindUnique(
+		db.Teacher.ID.Equals(id),
+	).With(
+		db.Teacher.User.Fetch(),
+		db.Teacher.Programs.Fetch(livePrograms()...),
+		db.Teacher.Courses.Fetch(liveCourses()...),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Teacher{}, err
+	}
+	return teacherModel(*teacher), nil
+}
+
+func teacherModel(teacher db.TeacherModel) *model.Teacher {
+	programs := []*model.Program{}
+	for _, program := range teacher.Programs() {
+		programs = append(programs, &model.Program{
+			ID:          program.ID,
+			Name:        program.Name,
+			Description: program.Description,
+			TeacherID:   teacher.ID,
+			Version:     program.Version,
+		})
+	}
+	courses := []*model.Course{}
+	for _, course := range teacher.Courses() {
+		ploGroupID, _ := course.PloGroupID()
+		courses = append(courses, &model.Course{
+			ID:          course.ID,
+			Name:        course.Name,
+			Description: course.Description,
+			Semester:    course.Semester,
+			Year:        course.Year,
+			PloGroupID:  ploGroupID,
+			ProgramID:   course.ProgramID,
+			TeacherID:   teacher.ID,
+			Version:     course.Version,
+		})
+	}
+	return &model.Teacher{
+		ID:       teacher.ID,
+		Email:    teacher.User().Email,
+		Name:     teacher.User().Name,
+		Surname:  teacher.User().Surname,
+		Role:     teacher.Role,
+		Active:   teacher.Active,
+		Programs: programs,
+		Courses:  courses,
+	}
+}
+
+// keepDeveloper refuses changes that would leave no active developer, since
+// nobody could manage teacher accounts afterwards.
+func (r *Resolver) keepDeveloper(ctx context.Context, id string) error {
+	developers, err := r.Client.Teacher.FindMany(
+		db.Teacher.Role.Equals(roleDeveloper),
+		db.Teacher.Active.Equals(true),
+		db.Teacher.Not(db.Teacher.ID.Equals(id)),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if len(developers) == 0 {
+		return errors.New("there must be at least one active developer")
+	}
+	return nil
+}
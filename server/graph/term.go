@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"errors"
+)
+
+// A course belongs to a term, which gives its semester and year a place in
+// time. Courses created before terms existed keep their own semester and
+// year, and are linked when a term with the same semester and year is
+// created.
+
+func (r *Resolver) term(ctx context.Context, id string) (*model.Term, error) {
+	term, err := r.Client.Term.FindUnique(
+		db.Term.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return &model.Term{}, err
+	}
+	return termModel(*term), nil
+}
+
+func termModel(term db.TermModel) *model.Term {
+	return &model.Term{
+		ID:        term.ID,
+		Code:      term.Code,
+		Semester:  term.Semester,
+		Year:      term.Year,
+		StartDate: term.StartDate,
+		EndDate:   term.EndDate,
+		Status:    model.TermStatus(term.Status),
+	}
+}
+
+func validateTermInput(input model.CreateTermInput) error {
+	errs := inputErrors{}
+	if input.Code == "" {
+		errs.add("input.code", "code can't be empty")
+	}
+	if input.Semester < 1 {
+		errs.add("input.semester", "semester must be at least 1")
+	}
+	if !input.EndDate.After(input.StartDate) {
+		errs.add("input.endDate", "endDate must be after startDate")
+	}
+	return errs.err()
+}
+
+// courseTerm checks the term named in a course's input, and takes the
+// semester and year from the term so the two can't disagree.
+func (r *Resolver) courseTerm(ctx context.Context, input *model.CreateCourseInput) error {
+	if input.TermID == nil {
+		return nil
+	}
+	term, err := r.Client.Term.FindUnique(
+		db.Term.ID.Equals(*input.TermID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if model.TermStatus(term.Status) == model.TermStatusClosed {
+		return errors.New("term is closed")
+	}
+	input.Semester = term.Semester
+	input.Year = term.Year
+	return nil
+}
+
+// courseTermParams links a course to the term named in its input, see
+// courseTerm. Without a termID the course's term is left as it is.
+func (r *Resolver) courseTermParams(ctx context.Context, input *model.CreateCourseInput) ([]db.CourseSetParam, error) {
+	if input.TermID == nil {
+		return nil, nil
+	}
+	if err := r.courseTerm(ctx, input); err != nil {
+		return nil, err
+	}
+	return []db.CourseSetParam{
+		db.Course.Term.Link(
+			db.Term.ID.Equals(*input.TermID),
+		),
+	}, nil
+}
+
+// checkTermUnlocked rejects a change to a term's semester or year while any
+// of its courses is finalized, since the courses take theirs from the term.
+func (r *Resolver) checkTermUnlocked(ctx context.Context, id string, input model.CreateTermInput) error {
+	term, err := r.Client.Term.FindUnique(
+		db.Term.ID.Equals(id),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if term.Semester == input.Semester && term.Year == input.Year {
+		return nil
+	}
+	finalized, err := r.Client.Course.FindFirst(
+		db.Course.TermID.Equals(id),
+		db.Course.Not(db.Course.FinalizedAt.IsNull()),
+	).Exec(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return finalizedError(finalized.ID)
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"context"
+	"time"
+)
+
+// DefaultTrashRetention is how long trashed items are kept when
+// Resolver.TrashRetention isn't set.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// Programs, PLO groups, courses and quizzes are moved to the trash by setting
+// deletedAt instead of being deleted, so nothing underneath them is lost until
+// PurgeTrash removes them for good. The live* filters hide trashed rows, and
+// rows whose parent is trashed, from queries.
+
+func livePrograms() []db.ProgramWhereParam {
+	return []db.ProgramWhereParam{
+		db.Program.DeletedAt.IsNull(),
+	}
+}
+
+func livePLOGroups() []db.PLOgroupWhereParam {
+	return []db.PLOgroupWhereParam{
+		db.PLOgroup.DeletedAt.IsNull(),
+		db.PLOgroup.Program.Where(livePrograms()...),
+	}
+}
+
+// liveCourses also takes filters on the course's program, for the same
+// reason as liveQuizzes.
+func liveCourses(program ...db.ProgramWhereParam) []db.CourseWhereParam {
+	return []db.CourseWhereParam{
+		db.Course.DeletedAt.IsNull(),
+		db.Course.Program.Where(append(livePrograms(), program...)...),
+	}
+}
+
+// liveQuizzes also takes filters on the quiz's course, since a second
+// Course.Where would replace the one hiding trashed courses.
+func liveQuizzes(course ...db.CourseWhereParam) []db.QuizWhereParam {
+	return []db.QuizWhereParam{
+		db.Quiz.DeletedAt.IsNull(),
+		db.Quiz.Course.Where(append(liveCourses(), course...)...),
+	}
+}
+
+func (r *Resolver) trashRetention() time.Duration {
+	if r.TrashRetention <= 0 {
+		return DefaultTrashRetention
+	}
+	return r.TrashRetention
+}
+
+func (r *Resolver) trashItem(trashType model.TrashType, id string, name string, deletedAt time.Time, trashed bool) *model.TrashItem {
+	item := &model.TrashItem{
+		Type: trashType,
+		ID:   id,
+		Name: name,
+	}
+	if trashed {
+		purgeAt := deletedAt.Add(r.trashRetention())
+		item.DeletedAt = &deletedAt
+		item.PurgeAt = &purgeAt
+	}
+	return item
+}
+
+// setDeletedAt moves an item to the trash, or restores it when deletedAt is
+// nil. An item that is already in the trash keeps its deletedAt, so trashing
+// it again doesn't put off its purge.
+func (r *Resolver) setDeletedAt(ctx context.Context, trashType model.TrashType, id string, deletedAt *time.Time) (*model.TrashItem, error) {
+	switch trashType {
+	case model.TrashTypeProgram:
+		where := []db.ProgramWhereParam{db.Program.ID.Equals(id)}
+		if deletedAt != nil {
+			where = append(where, db.Program.DeletedAt.IsNull())
+		}
+		if _, err := r.Client.Program.FindMany(where...).Update(
+			db.Program.DeletedAt.SetOptional(deletedAt),
+		).Exec(ctx); err != nil {
+			return &model.TrashItem{}, err
+		}
+		program, err := r.Client.Program.FindUnique(
+			db.Program.ID.Equals(id),
+		).Exec(ctx)
+		if err != nil {
+			return &model.TrashItem{}, err
+		}
+		trashedAt, trashed := program.DeletedAt()
+		return r.trashItem(trashType, program.ID, program.Name, trashedAt, trashed), nil
+	case model.TrashTypePloGroup:
+		where := []db.PLOgroupWhereParam{db.PLOgroup.ID.Equals(id)}
+		if deletedAt != nil {
+			where = append(where, db.PLOgroup.DeletedAt.IsNull())
+		}
+		if _, err := r.Client.PLOgroup.FindMany(where...).Update(
+			db.PLOgroup.DeletedAt.SetOptional(deletedAt),
+		).Exec(ctx); err != nil {
+			return &model.TrashItem{}, err
+		}
+		ploGroup, err := r.Client.PLOgroup.FindUnique(
+			db.PLOgroup.ID.Equals(id),
+		).Exec(ctx)
+		if err != nil {
+			return &model.TrashItem{}, err
+		}
+		trashedAt, trashed := ploGroup.DeletedAt()
+		return r.trashItem(trashType, ploGroup.ID, ploGroup.Name, trashedAt, trashed), nil
+	case model.TrashTypeCourse:
+		where := []db.CourseWhereParam{db.Course.ID.Equals(id)}
+		if deletedAt != nil {
+			where = append(where, db.Course.DeletedAt.IsNull())
+		}
+		if _, err := r.Client.Course.FindMany(where...).Update(
+			db.Course.DeletedAt.SetOptional(deletedAt),
+		).Exec(ctx); err != nil {
+			return &model.TrashItem{}, err
+		}
+		course, err := r.Client.Course.FindUnique(
+			db.Course.ID.Equals(id),
+		).Exec(ctx)
+		if err != nil {
+			return &model.TrashItem{}, err
+		}
+		trashedAt, trashed := course.DeletedAt()
+		return r.trashItem(trashType, course.ID, course.Name, trashedAt, trashed), nil
+	default:
+		where := []db.QuizWhereParam{db.Quiz.ID.Equals(id)}
+		if deletedAt != nil {
+			where = append(where, db.Quiz.DeletedAt.IsNull())
+		}
+		if _, err := r.Client.Quiz.FindMany(where...).Update(
+			db.Quiz.DeletedAt.SetOptional(deletedAt),
+		).Exec(ctx); err != nil {
+			return &model.TrashItem{}, err
+		}
+		quiz, err := r.Client.Quiz.FindUnique(
+			db.Quiz.ID.Equals(id),
+		).Exec(ctx)
+		if err != nil {
+			return &model.TrashItem{}, err
+		}
+		trashedAt, trashed := quiz.DeletedAt()
+		return r.trashItem(trashType, quiz.ID, quiz.Name, trashedAt, trashed), nil
+	}
+}
+
+// PurgeTrash deletes everything that has been in the trash for longer than
+// the retention period. Prisma's cascades then remove what was underneath.
+func (r *Resolver) PurgeTrash(ctx context.Context) error {
+	before := time.Now().Add(-r.trashRetention())
+	if _, err := r.Client.Quiz.FindMany(
+		db.Quiz.DeletedAt.Lt(before),
+	).Delete().Exec(ctx); err != nil {
+		return err
+	}
+	if _, err := r.Client.Course.FindMany(
+		db.Course.DeletedAt.Lt(before),
+	).Delete().Exec(ctx); err != nil {
+		return err
+	}
+	if _, err := r.Client.PLOgroup.FindMany(
+		db.PLOgroup.DeletedAt.Lt(before),
+	).Delete().Exec(ctx); err != nil {
+		return err
+	}
+	_, err := r.Client.Program.FindMany(
+		db.Program.DeletedAt.Lt(before),
+	).Delete().Exec(ctx)
+	return err
+}
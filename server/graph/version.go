@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"api/server/db"
+
+	"github.com/prisma/prisma-client-go/runtime/transaction"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Programs, courses, PLOs, LOs and quizzes carry a version that every edit
+// increments. An edit that passes the version it last read only succeeds if
+// nobody changed the record in between; otherwise it fails with a CONFLICT
+// error that carries the record as it is now.
+//
+// An edit is checked first and then written by updateVersioned as one
+// conditional update that also increments the version. The update only
+// matches the record at the expected version, so of two concurrent edits
+// only one gets through, and an edit that fails its checks leaves the
+// version alone.
+
+func conflictError(current interface{}) error {
+	return &gqlerror.Error{
+		Message: "the record was changed by someone else",
+		Extensions: map[string]interface{}{
+			"code":    "CONFLICT",
+			"current": current,
+		},
+	}
+}
+
+// updateVersioned runs a conditional update of a record's fields and
+// version. If it matched nothing, current loads the record for the CONFLICT
+// error, or fails if the record is gone.
+func updateVersioned(update func() (*db.BatchResult, error), current func() (interface{}, error)) error {
+	updated, err := update()
+	if err != nil {
+		return err
+	}
+	if updated.Count > 0 {
+		return nil
+	}
+	record, err := current()
+	if err != nil {
+		return err
+	}
+	return conflictError(record)
+}
+
+// bumpCourseVersion returns a write that increments the version of the
+// courses matching the filters. Changes to a course's LO links bump it too,
+// so an edit that depends on the links it read, such as moving the course to
+// another PLO group, conflicts with any link change made in between. The
+// write also locks the course until the transaction ends.
+func (r *Resolver) bumpCourseVersion(course ...db.CourseWhereParam) transaction.Param {
+	return r.Client.Course.FindMany(
+		course...,
+	).Update(
+		db.Course.Version.Increment(1),
+	).Tx()
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"api/server/db"
+	"api/server/graph/model"
+	"errors"
+)
+
+// An LO's link to a PLO carries a weight and the level at which the LO
+// contributes to the PLO. A PLO's attainment is the weighted mean of the
+// scores that reach it through its links, so a link with weight 2 counts
+// twice as much as one with weight 1.
+
+func validateLinkWeight(weight float64) error {
+	if weight <= 0 {
+		return errors.New("weight must be greater than 0")
+	}
+	return nil
+}
+
+// loLinkModel needs the link loaded with its PLO.
+func loLinkModel(link db.LOlinkModel) *model.LOPLOLink {
+	return &model.LOPLOLink{
+		ID:           link.PloID,
+		Title:        link.Plo().Title,
+		Description:  link.Plo().Description,
+		PloGroupID:   link.Plo().PloGroupID,
+		Version:      link.Plo().Version,
+		Weight:       link.Weight,
+		Contribution: linkContribution(link),
+	}
+}
+
+func linkContribution(link db.LOlinkModel) *model.PLOContribution {
+	contribution, ok := link.Contribution()
+	if !ok {
+		return nil
+	}
+	c := model.PLOContribution(contribution)
+	return &c
+}
+
+// linkParams copies a link's weight and contribution onto a new link.
+func linkParams(link db.LOlinkModel) []db.LOlinkSetParam {
+	params := []db.LOlinkSetParam{
+		db.LOlink.Weight.Set(link.Weight),
+	}
+	if contribution, ok := link.Contribution(); ok {
+		params = append(params, db.LOlink.Contribution.Set(contribution))
+	}
+	return params
+}
+
+// weightedMean adds a value with the given weight to a mean over total
+// weight so far.
+func weightedMean(mean float64, total float64, value float64, weight float64) float64 {
+	return (mean*total + value*weight) / (total + weight)
+}
+
+// A question's score is split across the LO levels it's linked to in
+// proportion to the weights of its links. linkShare returns the part of the
+// question that one of its links accounts for. The question must be loaded
+// with its links.
+func linkShare(question db.QuestionModel, link db.QuestionLinkModel) float64 {
+	var total float64
+	for _, l := range question.Links() {
+		total += l.Weight
+	}
+	return link.Weight / total
+}